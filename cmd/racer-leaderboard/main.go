@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/VxVxN/game/internal/leaderboardserver"
+)
+
+// verifyTimeout is how long a replay may take to verify, the game loads all of its assets first.
+const verifyTimeout = time.Minute
+
+func main() {
+	config := leaderboardserver.DefaultConfig
+	address := flag.String("addr", ":8080", "address to listen on")
+	storePath := flag.String("store", "leaderboard.json", "file the scores are kept in")
+	flag.IntVar(&config.SubmitBurst, "submit-burst", config.SubmitBurst, "submissions one address can make at once")
+	flag.DurationVar(&config.SubmitInterval, "submit-interval", config.SubmitInterval, "time until one more submission is allowed")
+	racer := flag.String("verify", "", "racer binary to verify the replays of submissions with, they aren't verified if empty")
+	verifyDir := flag.String("verify-dir", ".", "directory with the assets of the game to run the racer binary in")
+	verifyParallel := flag.Int("verify-parallel", 2, "replays verified at once")
+	flag.Parse()
+	// the token is read from the environment so it doesn't show up in the process list
+	config.AdminToken = os.Getenv("RACER_ADMIN_TOKEN")
+
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	store, err := leaderboardserver.OpenStore(*storePath)
+	if err != nil {
+		log.Fatalf("Failed to open store: %v", err)
+	}
+	if config.AdminToken == "" {
+		logger.Warn("RACER_ADMIN_TOKEN is not set, the admin endpoints are off")
+	}
+	if *racer != "" {
+		config.Verify = leaderboardserver.CommandVerifier(*racer, *verifyDir, *verifyParallel, verifyTimeout)
+	} else {
+		logger.Warn("-verify is not set, submitted scores are taken without checking them")
+	}
+
+	server := &http.Server{
+		Addr:              *address,
+		Handler:           leaderboardserver.New(store, config, logger).Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      2*verifyTimeout + 10*time.Second, // waiting for a free verifier and verifying
+	}
+	logger.Info("Leaderboard listening", "address", *address, "store", *storePath)
+	if err = server.ListenAndServe(); err != nil {
+		log.Fatalf("Failed to serve: %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package achievements
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/VxVxN/game/pkg/atomicfile"
+)
+
+type EventKind int
+
+const (
+	RunStartEvent EventKind = iota
+	TickEvent               // a tick of the run has passed with the car still on the road
+	NearMissEvent
+	PassEvent // the car has overtaken a vehicle
+	CollisionEvent
+	RunEndEvent // the game is over
+)
+
+// Event is something that has happened in a run.
+type Event struct {
+	Kind    EventKind
+	Points  int    // TickEvent
+	Count   int    // NearMissEvent
+	Vehicle string // PassEvent
+	Cause   string // CollisionEvent
+}
+
+// run is what has happened in the current run.
+type run struct {
+	points     int
+	nearMisses int
+}
+
+type Achievement struct {
+	ID          string
+	Name        string
+	Description string
+	met         func(tracker *Tracker) bool
+	// goal is the total the achievement needs, it shows how far the player is, 0 if it is met in a single run
+	goal  int
+	total string
+}
+
+// All are the achievements in the order they are shown.
+var All = []Achievement{
+	{ID: "points_1000", Name: "Four digits", Description: "Score 1000 points in a run", met: func(tracker *Tracker) bool {
+		return tracker.run.points >= 1000
+	}},
+	{ID: "points_10000", Name: "High roller", Description: "Score 10000 points in a run", met: func(tracker *Tracker) bool {
+		return tracker.run.points >= 10000
+	}},
+	{ID: "near_misses_50", Name: "Close shave", Description: "Have 50 near misses in a run", met: func(tracker *Tracker) bool {
+		return tracker.run.nearMisses >= 50
+	}},
+	{ID: "long_trucks_100", Name: "Convoy", Description: "Pass 100 long trucks", goal: 100, total: "passed_long_truck"},
+	{ID: "cars_1000", Name: "Rush hour", Description: "Pass 1000 cars", goal: 1000, total: "passed_car"},
+	{ID: "first_crash", Name: "Fender bender", Description: "Crash for the first time", goal: 1, total: "crashes"},
+	{ID: "runs_100", Name: "Regular", Description: "Drive 100 runs", goal: 100, total: "runs"},
+}
+
+func (achievement Achievement) Met(tracker *Tracker) bool {
+	if achievement.met != nil {
+		return achievement.met(tracker)
+	}
+	return tracker.Totals[achievement.total] >= achievement.goal
+}
+
+// Progress returns how far the player is with an achievement of many runs, the goal is 0 for one of a single run.
+func (achievement Achievement) Progress(tracker *Tracker) (int, int) {
+	if achievement.met != nil {
+		return 0, 0
+	}
+	return min(tracker.Totals[achievement.total], achievement.goal), achievement.goal
+}
+
+// Tracker keeps the achievements of a profile and the totals of all of their runs.
+type Tracker struct {
+	path     string
+	Unlocked map[string]time.Time
+	Totals   map[string]int
+	run      run
+	// unsaved keeps Save from writing over a file that couldn't be read
+	unsaved bool
+}
+
+func newTracker(path string) *Tracker {
+	return &Tracker{
+		path:     path,
+		Unlocked: make(map[string]time.Time),
+		Totals:   make(map[string]int),
+	}
+}
+
+// Load reads the achievements from the file at path. A tracker with nothing unlocked is returned with the error
+// of a file that can't be read. A file that can't be decoded is kept with the .corrupt extension, one that
+// can't be read at all isn't written over.
+func Load(path string) (*Tracker, error) {
+	tracker := newTracker(path)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tracker, nil
+	}
+	if err != nil {
+		tracker.unsaved = true
+		return tracker, err
+	}
+	if err = json.Unmarshal(data, tracker); err != nil {
+		tracker = newTracker(path)
+		if renameErr := os.Rename(path, path+".corrupt"); renameErr != nil {
+			tracker.unsaved = true
+			return tracker, errors.Join(err, renameErr)
+		}
+		return tracker, err
+	}
+	if tracker.Unlocked == nil {
+		tracker.Unlocked = make(map[string]time.Time)
+	}
+	if tracker.Totals == nil {
+		tracker.Totals = make(map[string]int)
+	}
+	return tracker, nil
+}
+
+// Save replaces the file at once, nothing is saved over a file Load couldn't read.
+func (tracker *Tracker) Save() error {
+	if tracker.unsaved {
+		return errors.New("the achievements file couldn't be read, it is kept until the profile is loaded again")
+	}
+	data, err := json.MarshalIndent(tracker, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(tracker.path, data)
+}
+
+// Handle counts the event and returns the achievements it has unlocked.
+func (tracker *Tracker) Handle(event Event, now time.Time) []Achievement {
+	switch event.Kind {
+	case RunStartEvent:
+		tracker.run = run{}
+	case TickEvent:
+		tracker.run.points = event.Points
+	case NearMissEvent:
+		tracker.run.nearMisses += event.Count
+	case PassEvent:
+		tracker.Totals["passed_"+event.Vehicle]++
+	case CollisionEvent:
+		tracker.Totals["crashes"]++
+		tracker.Totals["crashes_"+event.Cause]++
+	case RunEndEvent:
+		tracker.Totals["runs"]++
+	}
+
+	var unlocked []Achievement
+	for _, achievement := range All {
+		if _, ok := tracker.Unlocked[achievement.ID]; ok || !achievement.Met(tracker) {
+			continue
+		}
+		tracker.Unlocked[achievement.ID] = now
+		unlocked = append(unlocked, achievement)
+	}
+	return unlocked
+}
@@ -0,0 +1,143 @@
+package biome
+
+import (
+	"math/rand/v2"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/VxVxN/game/internal/cargenerator"
+	"github.com/VxVxN/game/internal/scenery"
+)
+
+const (
+	lengthMetres = 3000 // how long a biome lasts
+	fadeMetres   = 300  // the last part of a biome where it crossfades into the next one
+)
+
+type Biome struct {
+	Name    string
+	Road    *ebiten.Image
+	Scenery []scenery.Kind
+	Traffic cargenerator.TrafficMix
+	Grade   ebiten.ColorScale
+}
+
+func Defaults(road *ebiten.Image) []*Biome {
+	return []*Biome{
+		{
+			Name:    "City",
+			Road:    road,
+			Scenery: []scenery.Kind{scenery.Building, scenery.Sign},
+			Traffic: cargenerator.TrafficMix{Cars: 1, Trucks: 0.6, LongTrucks: 0.3},
+			Grade:   newGrade(1, 1, 1),
+		},
+		{
+			Name:    "Countryside",
+			Road:    tintRoad(road, 0.95, 1, 0.9),
+			Scenery: []scenery.Kind{scenery.Tree, scenery.Fence, scenery.Sign},
+			Traffic: cargenerator.TrafficMix{Cars: 0.7, Trucks: 1, LongTrucks: 0.6},
+			Grade:   newGrade(1, 1.05, 0.95),
+		},
+		{
+			Name:    "Desert",
+			Road:    tintRoad(road, 1.15, 1.05, 0.85),
+			Scenery: []scenery.Kind{scenery.Sign},
+			Traffic: cargenerator.TrafficMix{Cars: 0.5, Trucks: 0.7, LongTrucks: 1},
+			Grade:   newGrade(1.1, 1, 0.8),
+		},
+		{
+			Name:    "Winter",
+			Road:    tintRoad(road, 1.05, 1.1, 1.2),
+			Scenery: []scenery.Kind{scenery.Tree, scenery.Fence},
+			Traffic: cargenerator.TrafficMix{Cars: 0.8, Trucks: 0.8, LongTrucks: 0.4},
+			Grade:   newGrade(0.9, 0.97, 1.15),
+		},
+	}
+}
+
+func newGrade(r, g, b float32) ebiten.ColorScale {
+	var grade ebiten.ColorScale
+	grade.Scale(r, g, b, 1)
+	return grade
+}
+
+func tintRoad(road *ebiten.Image, r, g, b float32) *ebiten.Image {
+	tinted := ebiten.NewImage(road.Bounds().Dx(), road.Bounds().Dy())
+	op := &ebiten.DrawImageOptions{}
+	op.ColorScale.Scale(r, g, b, 1)
+	tinted.DrawImage(road, op)
+	return tinted
+}
+
+// Cycle switches biomes every few kilometres in an order picked by the run seed.
+type Cycle struct {
+	biomes  []*Biome
+	order   []int
+	fixed   int
+	current int
+	blend   float64
+}
+
+func NewCycle(biomes []*Biome) *Cycle {
+	cycle := &Cycle{biomes: biomes, fixed: -1}
+	cycle.Reset(0)
+	return cycle
+}
+
+func (cycle *Cycle) Reset(seed uint64) {
+	random := rand.New(rand.NewPCG(seed, seed^0xb10e))
+	cycle.order = random.Perm(len(cycle.biomes))
+	if cycle.fixed >= 0 {
+		cycle.order = []int{cycle.fixed}
+	}
+	cycle.current = 0
+	cycle.blend = 0
+}
+
+// Fix keeps a single biome for the whole run starting with the next reset, an empty or unknown name lets the biomes cycle.
+func (cycle *Cycle) Fix(name string) {
+	cycle.fixed = -1
+	for i, biome := range cycle.biomes {
+		if biome.Name == name {
+			cycle.fixed = i
+		}
+	}
+}
+
+// Update moves the cycle to the given distance in metres and reports whether the current biome changed.
+func (cycle *Cycle) Update(distance float64) bool {
+	index := int(distance / lengthMetres)
+	changed := index != cycle.current
+	cycle.current = index
+
+	cycle.blend = 0
+	if into := distance - float64(index)*lengthMetres; into > lengthMetres-fadeMetres {
+		cycle.blend = (into - (lengthMetres - fadeMetres)) / fadeMetres
+	}
+	return changed
+}
+
+func (cycle *Cycle) Current() *Biome {
+	return cycle.biomes[cycle.order[cycle.current%len(cycle.order)]]
+}
+
+func (cycle *Cycle) Next() *Biome {
+	return cycle.biomes[cycle.order[(cycle.current+1)%len(cycle.order)]]
+}
+
+// Blend is how far the crossfade into the next biome has gone, from 0 to 1.
+func (cycle *Cycle) Blend() float64 {
+	return cycle.blend
+}
+
+// Grade returns the ambient colour grading mixed between the current and the next biome.
+func (cycle *Cycle) Grade() ebiten.ColorScale {
+	current, next := cycle.Current().Grade, cycle.Next().Grade
+	blend := float32(cycle.blend)
+	var grade ebiten.ColorScale
+	grade.SetR(current.R()*(1-blend) + next.R()*blend)
+	grade.SetG(current.G()*(1-blend) + next.G()*blend)
+	grade.SetB(current.B()*(1-blend) + next.B()*blend)
+	grade.SetA(1)
+	return grade
+}
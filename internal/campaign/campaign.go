@@ -0,0 +1,188 @@
+package campaign
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/VxVxN/game/internal/cargenerator"
+	"github.com/VxVxN/game/internal/traffic"
+	"github.com/VxVxN/game/pkg/atomicfile"
+)
+
+type ObjectiveKind string
+
+const (
+	// ObjectiveDistance is met by driving at least Target metres.
+	ObjectiveDistance ObjectiveKind = "distance"
+	// ObjectiveFuel is met by collecting at least Target fuel cans.
+	ObjectiveFuel ObjectiveKind = "fuel"
+	// ObjectiveMaxNearMisses is met by having no more than Target near misses, 0 means none at all.
+	ObjectiveMaxNearMisses ObjectiveKind = "max_near_misses"
+)
+
+type Objective struct {
+	Kind   ObjectiveKind
+	Target float64
+}
+
+// Progress is what the player achieved in a run, it is checked against the objectives.
+type Progress struct {
+	Distance   float64
+	FuelCans   int
+	NearMisses int
+}
+
+func (objective Objective) Met(progress Progress) bool {
+	switch objective.Kind {
+	case ObjectiveDistance:
+		return progress.Distance >= objective.Target
+	case ObjectiveFuel:
+		return float64(progress.FuelCans) >= objective.Target
+	case ObjectiveMaxNearMisses:
+		return float64(progress.NearMisses) <= objective.Target
+	}
+	return false
+}
+
+func (objective Objective) String() string {
+	switch objective.Kind {
+	case ObjectiveDistance:
+		return fmt.Sprintf("Reach %.1f km", objective.Target/1000)
+	case ObjectiveFuel:
+		return fmt.Sprintf("Collect %d fuel cans", int(objective.Target))
+	case ObjectiveMaxNearMisses:
+		if objective.Target == 0 {
+			return "No near misses"
+		}
+		return fmt.Sprintf("At most %d near misses", int(objective.Target))
+	}
+	return string(objective.Kind)
+}
+
+type Level struct {
+	path       string
+	ID         string `json:"-"`
+	Name       string
+	Biome      string
+	Length     float64 // metres
+	Hazards    bool
+	Traffic    *cargenerator.TrafficMix `json:",omitempty"`
+	Waves      string                   `json:",omitempty"` // traffic script, relative to the level file
+	Script     *traffic.Script          `json:"-"`
+	Objectives []Objective
+	Stars      [2]int // points needed for the second and the third star
+}
+
+// Rating returns 1-3 stars for a cleared level.
+func (level *Level) Rating(points int) int {
+	stars := 1
+	for _, threshold := range level.Stars {
+		if threshold > 0 && points >= threshold {
+			stars++
+		}
+	}
+	return stars
+}
+
+func (level *Level) Cleared(progress Progress) bool {
+	for _, objective := range level.Objectives {
+		if !objective.Met(progress) {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadLevels reads every level file in the directory, the campaign order is the order of the file names.
+func LoadLevels(dir string) ([]*Level, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read levels directory: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	levels := make([]*Level, 0, len(names))
+	for _, name := range names {
+		level, err := LoadLevel(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+func LoadLevel(path string) (*Level, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read level: %v", err)
+	}
+	level := &Level{path: path}
+	if err = json.Unmarshal(data, level); err != nil {
+		return nil, fmt.Errorf("failed to parse level %s: %v", path, err)
+	}
+	level.ID = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if level.Length <= 0 {
+		return nil, fmt.Errorf("level %s: length must be positive", path)
+	}
+	if level.Waves != "" {
+		level.Script, err = traffic.Load(filepath.Join(filepath.Dir(path), level.Waves))
+		if err != nil {
+			return nil, fmt.Errorf("level %s: %w", path, err)
+		}
+	}
+	return level, nil
+}
+
+// Clone returns a copy that can be edited without changing the level.
+func (level *Level) Clone() *Level {
+	clone := *level
+	if level.Traffic != nil {
+		mix := *level.Traffic
+		clone.Traffic = &mix
+	}
+	if level.Script != nil {
+		clone.Script = level.Script.Clone()
+	}
+	clone.Objectives = slices.Clone(level.Objectives)
+	return &clone
+}
+
+// Save writes the level and its traffic script, a level without a script file gets one named after the level.
+func (level *Level) Save() error {
+	if level.Script != nil {
+		if err := level.Script.Validate(); err != nil {
+			return err
+		}
+		if level.Waves == "" {
+			level.Waves = "waves/" + level.ID + ".json"
+		}
+		scriptPath := filepath.Join(filepath.Dir(level.path), level.Waves)
+		if err := os.MkdirAll(filepath.Dir(scriptPath), 0755); err != nil {
+			return fmt.Errorf("failed to create traffic script directory: %v", err)
+		}
+		if err := level.Script.Save(scriptPath); err != nil {
+			return fmt.Errorf("failed to save traffic script: %v", err)
+		}
+	}
+	data, err := json.MarshalIndent(level, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = atomicfile.Write(level.path, data); err != nil {
+		return fmt.Errorf("failed to save level: %v", err)
+	}
+	return nil
+}
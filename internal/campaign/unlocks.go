@@ -0,0 +1,136 @@
+package campaign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/VxVxN/game/pkg/atomicfile"
+)
+
+// ErrCorrupt is returned by LoadUnlocks for a file that can't be read back, RestoreBackup or Reset repair it.
+var ErrCorrupt = errors.New("the campaign progress is corrupt")
+
+// Unlocks stores the best star rating of every cleared level per profile.
+type Unlocks struct {
+	path     string
+	Profiles map[string]map[string]int
+	// corrupt keeps Save from writing over a file that couldn't be read until it is repaired
+	corrupt bool
+}
+
+// LoadUnlocks reads the progress from the file at path. A file that can't be decoded gives no progress
+// together with ErrCorrupt, and isn't written over until RestoreBackup or Reset repair it.
+func LoadUnlocks(path string) (*Unlocks, error) {
+	unlocks := &Unlocks{path: path}
+	err := unlocks.read(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return unlocks, nil
+	}
+	if errors.Is(err, ErrCorrupt) {
+		unlocks.corrupt = true
+		return unlocks, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return unlocks, nil
+}
+
+// read replaces the progress with the one in the file, it is left empty if the file can't be read.
+func (unlocks *Unlocks) read(path string) error {
+	unlocks.Profiles = make(map[string]map[string]int)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var read Unlocks
+	if err = json.Unmarshal(data, &read); err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	if read.Profiles != nil {
+		unlocks.Profiles = read.Profiles
+	}
+	return nil
+}
+
+func (unlocks *Unlocks) Path() string {
+	return unlocks.path
+}
+
+func (unlocks *Unlocks) backupPath() string {
+	return atomicfile.BackupPath(unlocks.path)
+}
+
+func (unlocks *Unlocks) Stars(profile, levelID string) int {
+	return unlocks.Profiles[profile][levelID]
+}
+
+// SetStars keeps the best rating of the level and reports whether it improved.
+func (unlocks *Unlocks) SetStars(profile, levelID string, stars int) bool {
+	if unlocks.Stars(profile, levelID) >= stars {
+		return false
+	}
+	if unlocks.Profiles[profile] == nil {
+		unlocks.Profiles[profile] = make(map[string]int)
+	}
+	unlocks.Profiles[profile][levelID] = stars
+	return true
+}
+
+// Unlocked reports whether the level at the index can be played, clearing a level unlocks the next one.
+func (unlocks *Unlocks) Unlocked(profile string, levels []*Level, index int) bool {
+	return index == 0 || unlocks.Stars(profile, levels[index-1].ID) > 0
+}
+
+// Forget removes the ratings of a deleted profile.
+func (unlocks *Unlocks) Forget(profile string) {
+	delete(unlocks.Profiles, profile)
+}
+
+// Save writes the progress unless the file is corrupt, RestoreBackup can go back to the previous one.
+func (unlocks *Unlocks) Save() error {
+	if unlocks.corrupt {
+		return fmt.Errorf("%w, it is kept until it is repaired", ErrCorrupt)
+	}
+	data, err := json.Marshal(unlocks)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteWithBackup(unlocks.path, data)
+}
+
+// HasBackup reports whether there is a backup RestoreBackup can read.
+func (unlocks *Unlocks) HasBackup() bool {
+	return (&Unlocks{}).read(unlocks.backupPath()) == nil
+}
+
+// RestoreBackup goes back to the progress of the backup, the corrupt file is kept with the .corrupt extension.
+func (unlocks *Unlocks) RestoreBackup() error {
+	if err := unlocks.read(unlocks.backupPath()); err != nil {
+		return fmt.Errorf("failed to read backup: %v", err)
+	}
+	if err := unlocks.moveAside(); err != nil {
+		return err
+	}
+	return unlocks.Save()
+}
+
+// Reset starts without progress instead of a corrupt file, which is kept with the .corrupt extension.
+func (unlocks *Unlocks) Reset() error {
+	if err := unlocks.moveAside(); err != nil {
+		return err
+	}
+	unlocks.Profiles = make(map[string]map[string]int)
+	return unlocks.Save()
+}
+
+func (unlocks *Unlocks) moveAside() error {
+	err := os.Rename(unlocks.path, unlocks.path+".corrupt")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to move the corrupt file aside: %v", err)
+	}
+	unlocks.corrupt = false
+	return nil
+}
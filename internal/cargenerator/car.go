@@ -14,6 +14,33 @@ type Car struct {
 	image  *ebiten.Image
 	shadow *shadow.Shadow
 	lane   roadLane
+	kind   VehicleKind
+
+	nearMiss bool
+	passed   bool
+	parked   bool
+	// hold is how many pixels of road a parked vehicle waits before it is spawned again
+	hold float64
+}
+
+type VehicleKind int
+
+const (
+	CarKind VehicleKind = iota
+	TruckKind
+	LongTruckKind
+)
+
+func (kind VehicleKind) String() string {
+	switch kind {
+	case CarKind:
+		return "car"
+	case TruckKind:
+		return "truck"
+	case LongTruckKind:
+		return "long_truck"
+	}
+	return ""
 }
 
 type roadLane int
@@ -27,7 +54,7 @@ const (
 	FifthLane
 )
 
-func newCar(image *ebiten.Image, screenHeight, startRoad float64, shadow *shadow.Shadow) *Car {
+func newCar(image *ebiten.Image, kind VehicleKind, screenHeight, startRoad float64, shadow *shadow.Shadow) *Car {
 	return &Car{
 		Rectangle:    rectangle.New(0, 0, float64(image.Bounds().Dx()), float64(image.Bounds().Dy())),
 		screenHeight: screenHeight,
@@ -35,6 +62,7 @@ func newCar(image *ebiten.Image, screenHeight, startRoad float64, shadow *shadow
 		image:        image,
 		shadow:       shadow,
 		lane:         NoLane,
+		kind:         kind,
 	}
 }
 
@@ -11,48 +11,171 @@ import (
 
 type CarGenerator struct {
 	screenHeight float64
+	startRoad    float64
 	cars         []*Car
+	scripted     []*Car
+	images       map[VehicleKind][]*ebiten.Image
+	shadows      map[VehicleKind]*shadow.Shadow
+	rand         *rand.Rand
 	freeLane     [5]int
+	blockedLanes [5]bool
+	trafficMix   TrafficMix
+}
+
+// TrafficMix is the chance of each vehicle kind to enter the road when it is respawned.
+type TrafficMix struct {
+	Cars       float64
+	Trucks     float64
+	LongTrucks float64
+}
+
+var DefaultTrafficMix = TrafficMix{Cars: 1, Trucks: 1, LongTrucks: 1}
+
+func (mix TrafficMix) chance(kind VehicleKind) float64 {
+	switch kind {
+	case TruckKind:
+		return mix.Trucks
+	case LongTruckKind:
+		return mix.LongTrucks
+	}
+	return mix.Cars
 }
 
 func New(carImages, truckImages, longTruckImages []*ebiten.Image, screenHeight, startRoad float64, carShadow, truckShadow, longTruckShadow *shadow.Shadow) *CarGenerator {
 	carGenerator := &CarGenerator{
 		screenHeight: screenHeight,
+		startRoad:    startRoad,
+		trafficMix:   DefaultTrafficMix,
+		rand:         rand.New(rand.NewPCG(0, 0)),
+		images: map[VehicleKind][]*ebiten.Image{
+			CarKind:       carImages,
+			TruckKind:     truckImages,
+			LongTruckKind: longTruckImages,
+		},
+		shadows: map[VehicleKind]*shadow.Shadow{
+			CarKind:       carShadow,
+			TruckKind:     truckShadow,
+			LongTruckKind: longTruckShadow,
+		},
 	}
 
 	carGenerator.cars = make([]*Car, 0, len(carImages))
 	for _, image := range carImages {
-		car := newCar(image, screenHeight, startRoad, carShadow)
+		car := newCar(image, CarKind, screenHeight, startRoad, carShadow)
 		carGenerator.cars = append(carGenerator.cars, car)
 	}
 	for _, image := range truckImages {
-		car := newCar(image, screenHeight, startRoad, truckShadow)
+		car := newCar(image, TruckKind, screenHeight, startRoad, truckShadow)
 		carGenerator.cars = append(carGenerator.cars, car)
 	}
 	for _, image := range longTruckImages {
-		car := newCar(image, screenHeight, startRoad, longTruckShadow)
+		car := newCar(image, LongTruckKind, screenHeight, startRoad, longTruckShadow)
 		carGenerator.cars = append(carGenerator.cars, car)
 	}
 	return carGenerator
 }
 
 func (generator *CarGenerator) Update(scrollSpeed float64) {
+	scripted := generator.scripted[:0]
+	for _, car := range generator.scripted {
+		car.Update(scrollSpeed)
+		if car.Y > car.screenHeight {
+			generator.freeLane[car.lane]--
+			continue
+		}
+		scripted = append(scripted, car)
+	}
+	generator.scripted = scripted
+
 	for i, car := range generator.cars {
+		if car.parked {
+			if car.hold > 0 {
+				car.hold -= scrollSpeed
+				continue
+			}
+			if len(generator.scripted) == 0 {
+				car.parked = false
+				generator.spawnCar(car, i)
+			}
+			continue
+		}
 		car.Update(scrollSpeed)
 		if car.Y > car.screenHeight {
-			generator.spawnCar(car, i)
+			generator.respawnCar(car, i)
+		}
+	}
+}
+
+// respawnCar parks the car while a scripted wave is on the road, so random traffic can't close the gaps the wave leaves.
+func (generator *CarGenerator) respawnCar(car *Car, i int) {
+	if len(generator.scripted) > 0 {
+		generator.parkCar(car)
+		return
+	}
+	generator.spawnCar(car, i)
+}
+
+func (generator *CarGenerator) parkCar(car *Car) {
+	if car.lane != NoLane {
+		generator.freeLane[car.lane]--
+	}
+	car.lane = NoLane
+	car.parked = true
+}
+
+// SpawnScripted puts a vehicle of the kind into the lane offset pixels above the top of the screen.
+// Random traffic that hasn't entered the screen yet is parked until the scripted vehicles are gone.
+func (generator *CarGenerator) SpawnScripted(kind VehicleKind, lane int, offset float64) {
+	images := generator.images[kind]
+	if len(images) == 0 || lane < 0 || lane >= len(generator.freeLane) {
+		return
+	}
+	for _, car := range generator.cars {
+		if !car.parked && car.Y+car.Height < 0 {
+			generator.parkCar(car)
 		}
 	}
+	car := newCar(images[generator.rand.IntN(len(images))], kind, generator.screenHeight, generator.startRoad, generator.shadows[kind])
+	car.X = generator.startRoad + float64(lane)*200 + 65
+	car.Y = -car.Height - offset
+	car.lane = roadLane(lane)
+	generator.freeLane[lane]++
+	generator.scripted = append(generator.scripted, car)
 }
 
+// vehicles returns everything that is on the road or about to enter it.
+func (generator *CarGenerator) vehicles() []*Car {
+	vehicles := make([]*Car, 0, len(generator.cars)+len(generator.scripted))
+	for _, car := range generator.cars {
+		if !car.parked {
+			vehicles = append(vehicles, car)
+		}
+	}
+	return append(vehicles, generator.scripted...)
+}
+
+// spawnAttempts is how many places are tried for a respawned vehicle before it is parked,
+// e.g. while every lane is blocked or full.
+const spawnAttempts = 100
+
 func (generator *CarGenerator) spawnCar(car *Car, i int) {
 	if car.lane != NoLane {
 		generator.freeLane[car.lane]--
 	}
-	for {
-		car.Y = float64(-200 - rand.IntN(1800))
+	car.lane = NoLane
+	car.nearMiss = false
+	car.passed = false
+	// a vehicle that misses its chance sits out the time it would have taken to cross the screen,
+	// so a kind the biome doesn't have never shows up
+	if generator.rand.Float64() >= generator.trafficMix.chance(car.kind) {
+		car.parked = true
+		car.hold = generator.screenHeight * 2
+		return
+	}
+	for range spawnAttempts {
+		car.Y = float64(-200 - generator.rand.IntN(1800))
 
-		lane := roadLane(rand.IntN(5))
+		lane := roadLane(generator.rand.IntN(5))
 		car.X = car.startRoad + float64(lane)*200 + 65 // 200 - this is the interval between the bands
 
 		if generator.freeLane[lane] == 3 {
@@ -60,7 +183,7 @@ func (generator *CarGenerator) spawnCar(car *Car, i int) {
 		}
 		var availableLane bool
 		for i, lineCarCounter := range generator.freeLane {
-			if lineCarCounter == 0 && roadLane(i) != lane {
+			if lineCarCounter == 0 && !generator.blockedLanes[i] && roadLane(i) != lane {
 				availableLane = true
 				break
 			}
@@ -68,11 +191,9 @@ func (generator *CarGenerator) spawnCar(car *Car, i int) {
 		if !availableLane {
 			continue
 		}
-		car.lane = lane
-		generator.freeLane[lane]++
 		var isCollision bool
 		for j, c := range generator.cars {
-			if i == j {
+			if i == j || c.parked {
 				continue
 			}
 			if car.Collision(c.Rectangle) {
@@ -81,21 +202,23 @@ func (generator *CarGenerator) spawnCar(car *Car, i int) {
 			}
 		}
 		if isCollision {
-			generator.freeLane[car.lane]--
 			continue
 		}
-		break
+		car.lane = lane
+		generator.freeLane[lane]++
+		return
 	}
+	car.parked = true // it is spawned again on the next update
 }
 
 func (generator *CarGenerator) Draw(screen *ebiten.Image) {
-	for _, car := range generator.cars {
+	for _, car := range generator.vehicles() {
 		car.Draw(screen)
 	}
 }
 
 func (generator *CarGenerator) Collision(rectangle *rectangle.Rectangle) bool {
-	for _, car := range generator.cars {
+	for _, car := range generator.vehicles() {
 		if car.Collision(rectangle) {
 			return true
 		}
@@ -103,13 +226,97 @@ func (generator *CarGenerator) Collision(rectangle *rectangle.Rectangle) bool {
 	return false
 }
 
-func (generator *CarGenerator) Reset() {
+const nearMissDistance = 30
+
+// NearMisses returns how many cars have just squeezed past the rectangle without touching it,
+// every car counts only once per pass.
+func (generator *CarGenerator) NearMisses(rectangle *rectangle.Rectangle) int {
+	var count int
+	for _, car := range generator.vehicles() {
+		if car.nearMiss || car.Y > rectangle.Y+rectangle.Height || car.Y+car.Height < rectangle.Y {
+			continue
+		}
+		gap := max(car.X-(rectangle.X+rectangle.Width), rectangle.X-(car.X+car.Width))
+		if gap > 0 && gap <= nearMissDistance {
+			car.nearMiss = true
+			count++
+		}
+	}
+	return count
+}
+
+// Passed returns the kinds of the vehicles that have just fallen behind the rectangle,
+// every vehicle counts only once per pass.
+func (generator *CarGenerator) Passed(rectangle *rectangle.Rectangle) []VehicleKind {
+	var kinds []VehicleKind
+	for _, car := range generator.vehicles() {
+		if !car.passed && car.Y > rectangle.Y+rectangle.Height {
+			car.passed = true
+			kinds = append(kinds, car.kind)
+		}
+	}
+	return kinds
+}
+
+// Hit moves the cars touching the rectangle off the road and reports whether there were any.
+func (generator *CarGenerator) Hit(rectangle *rectangle.Rectangle) bool {
+	var hit bool
+	scripted := generator.scripted[:0]
+	for _, car := range generator.scripted {
+		if car.Collision(rectangle) {
+			generator.freeLane[car.lane]--
+			hit = true
+			continue
+		}
+		scripted = append(scripted, car)
+	}
+	generator.scripted = scripted
+	for i, car := range generator.cars {
+		if !car.parked && car.Collision(rectangle) {
+			generator.respawnCar(car, i)
+			hit = true
+		}
+	}
+	return hit
+}
+
+// Reset puts the traffic back above the road, the same seed gives the same traffic for the same driving.
+func (generator *CarGenerator) Reset(seed uint64) {
+	generator.rand = rand.New(rand.NewPCG(seed, seed^0x7a3f))
+	generator.scripted = generator.scripted[:0]
+	generator.freeLane = [5]int{}
+	// clear the road first so nothing of the previous run decides where the cars go
+	for _, car := range generator.cars {
+		car.lane = NoLane
+		car.parked = false
+		car.hold = 0
+		car.Y = car.screenHeight * 2
+	}
 	for i, car := range generator.cars {
 		generator.spawnCar(car, i)
 	}
 }
 
+// EmptyLanes reports lanes without any traffic, the generator always keeps at least one of them passable.
+func (generator *CarGenerator) EmptyLanes() [5]bool {
+	var empty [5]bool
+	for i, lineCarCounter := range generator.freeLane {
+		empty[i] = lineCarCounter == 0
+	}
+	return empty
+}
+
+// SetBlockedLanes marks lanes closed by something other than traffic, they don't count as passable.
+func (generator *CarGenerator) SetBlockedLanes(blocked [5]bool) {
+	generator.blockedLanes = blocked
+}
+
+func (generator *CarGenerator) SetTrafficMix(mix TrafficMix) {
+	generator.trafficMix = mix
+}
+
 func (generator *CarGenerator) SetSunDirection(sunDirection shadow.DirectionShadow) {
+	// the scripted vehicles share the shadows of their kind
 	for _, car := range generator.cars {
 		car.SetSunDirection(sunDirection)
 	}
@@ -0,0 +1,176 @@
+package cargenerator
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/VxVxN/gamedevlib/rectangle"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/VxVxN/game/internal/shadow"
+)
+
+const (
+	chaserPace       = 0.45 // how fast the police drives compared to the player at the very top of the screen
+	chaserGapSpeed   = 12   // pixels per tick the gap changes at full pace difference
+	chaserSteerSpeed = 4
+	chaserLookAhead  = 300
+	crashBoost       = 0.5
+	boostDecay       = 0.005
+	flashTicks       = 15
+)
+
+// Chaser is a police car that follows the player from behind the bottom edge of the screen.
+type Chaser struct {
+	*rectangle.Rectangle
+	screenHeight float64
+	startRoad    float64
+	image        *ebiten.Image
+	shadow       *shadow.Shadow
+	gap          float64
+	escapeGap    float64
+	boost        float64
+	targetLane   roadLane
+	ticks        int
+}
+
+func NewChaser(image *ebiten.Image, screenHeight, startRoad float64, shadow *shadow.Shadow) *Chaser {
+	return &Chaser{
+		Rectangle:    rectangle.New(0, screenHeight, float64(image.Bounds().Dx()), float64(image.Bounds().Dy())),
+		screenHeight: screenHeight,
+		startRoad:    startRoad,
+		image:        image,
+		shadow:       shadow,
+		escapeGap:    screenHeight,
+		targetLane:   NoLane,
+	}
+}
+
+// Reset puts the police car behind the target, just below the screen.
+func (chaser *Chaser) Reset(target *rectangle.Rectangle) {
+	chaser.X = target.X
+	chaser.gap = chaser.screenHeight - target.Y - target.Height
+	chaser.Y = target.Y + target.Height + chaser.gap
+	chaser.boost = 0
+	chaser.ticks = 0
+	chaser.targetLane = NoLane
+}
+
+// Update moves the police car closer when the target is slow, which is how low it is on the screen.
+func (chaser *Chaser) Update(target *rectangle.Rectangle, generator *CarGenerator) {
+	chaser.ticks++
+	chaser.boost = max(chaser.boost-boostDecay, 0)
+
+	targetPace := 1 - target.Y/chaser.screenHeight
+	chaser.gap += (targetPace - chaserPace - chaser.boost) * chaserGapSpeed
+	chaser.gap = max(chaser.gap, 0)
+	chaser.Y = target.Y + target.Height + chaser.gap
+
+	chaser.steer(target, generator)
+}
+
+func (chaser *Chaser) steer(target *rectangle.Rectangle, generator *CarGenerator) {
+	targetX := target.X + target.Width/2 - chaser.Width/2
+	if chaser.targetLane != NoLane {
+		targetX = chaser.laneX(chaser.targetLane)
+		if math.Abs(chaser.X-targetX) < chaserSteerSpeed {
+			chaser.targetLane = NoLane
+		}
+	} else if car := chaser.carAhead(generator); car != nil {
+		// weave into a neighbouring lane that is clear
+		lane := chaser.lane()
+		for _, candidate := range []roadLane{lane - 1, lane + 1} {
+			if candidate < FirstLane || candidate > FifthLane {
+				continue
+			}
+			if !chaser.laneBusy(candidate, generator) {
+				chaser.targetLane = candidate
+				targetX = chaser.laneX(candidate)
+				break
+			}
+		}
+	}
+
+	switch {
+	case chaser.X < targetX-chaserSteerSpeed:
+		chaser.X += chaserSteerSpeed
+	case chaser.X > targetX+chaserSteerSpeed:
+		chaser.X -= chaserSteerSpeed
+	}
+}
+
+func (chaser *Chaser) carAhead(generator *CarGenerator) *Car {
+	ahead := rectangle.New(chaser.X, chaser.Y-chaserLookAhead, chaser.Width, chaserLookAhead)
+	for _, car := range generator.vehicles() {
+		if car.Collision(ahead) {
+			return car
+		}
+	}
+	return nil
+}
+
+func (chaser *Chaser) laneBusy(lane roadLane, generator *CarGenerator) bool {
+	area := rectangle.New(chaser.laneX(lane), chaser.Y-chaserLookAhead, chaser.Width, chaserLookAhead+chaser.Height)
+	for _, car := range generator.vehicles() {
+		if car.Collision(area) {
+			return true
+		}
+	}
+	return false
+}
+
+func (chaser *Chaser) lane() roadLane {
+	lane := roadLane((chaser.X - chaser.startRoad - 65 + 100) / 200)
+	return min(max(lane, FirstLane), FifthLane)
+}
+
+func (chaser *Chaser) laneX(lane roadLane) float64 {
+	return chaser.startRoad + float64(lane)*200 + 65
+}
+
+// Boost makes the police close in faster for a while, e.g. after the player crashed into traffic.
+func (chaser *Chaser) Boost() {
+	chaser.boost += crashBoost
+}
+
+// Heat is how close the police is, from 0 when the player is about to escape to 1 on contact.
+func (chaser *Chaser) Heat() float64 {
+	return min(max(1-chaser.gap/chaser.escapeGap, 0), 1)
+}
+
+func (chaser *Chaser) Escaped() bool {
+	return chaser.gap >= chaser.escapeGap
+}
+
+func (chaser *Chaser) Caught(target *rectangle.Rectangle) bool {
+	return chaser.gap <= 0 && chaser.X < target.X+target.Width && chaser.X+chaser.Width > target.X
+}
+
+// Lights returns the centre of the flashing light bar and whether the red half is lit.
+func (chaser *Chaser) Lights() (float64, float64, bool) {
+	return chaser.X + chaser.Width/2, chaser.Y + chaser.Height*0.48, (chaser.ticks/flashTicks)%2 == 0
+}
+
+func (chaser *Chaser) Draw(screen *ebiten.Image) {
+	if chaser.Y > chaser.screenHeight {
+		return
+	}
+	chaser.shadow.Draw(screen, chaser.X, chaser.Y)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(chaser.X, chaser.Y)
+	screen.DrawImage(chaser.image, op)
+
+	x, y, red := chaser.Lights()
+	redLight, blueLight := color.NRGBA{R: 255, G: 30, B: 30, A: 255}, color.NRGBA{R: 30, G: 120, B: 255, A: 80}
+	if !red {
+		redLight.A, blueLight.A = 80, 255
+	}
+	vector.DrawFilledCircle(screen, float32(x-20), float32(y), 12, redLight, true)
+	vector.DrawFilledCircle(screen, float32(x+20), float32(y), 12, blueLight, true)
+}
+
+func (chaser *Chaser) SetSunDirection(sunDirection shadow.DirectionShadow) {
+	chaser.shadow.SetDirection(sunDirection)
+}
@@ -0,0 +1,82 @@
+package dashboard
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	buttonPadding = 14
+	buttonSpacing = 10
+)
+
+// Command is what a click on the dashboard asks of the game, the filters are switched by the dashboard itself.
+type Command int
+
+const (
+	NoCommand Command = iota
+	RatingsCommand
+	ExitCommand
+)
+
+type button struct {
+	label string
+	box   area
+	click func() Command
+}
+
+// buttons are in the footer from the right, so the charts can be filtered with the mouse and by touch as well.
+func (dashboard *Dashboard) buttons() []button {
+	labels := []string{"Mode", "Profile", "Ratings", "Exit"}
+	clicks := []func() Command{
+		func() Command {
+			dashboard.SwitchMode(1)
+			return NoCommand
+		},
+		func() Command {
+			dashboard.SwitchProfile(1)
+			return NoCommand
+		},
+		func() Command { return RatingsCommand },
+		func() Command { return ExitCommand },
+	}
+	buttons := make([]button, len(labels))
+	x := dashboard.screenWidth - margin
+	for i := len(labels) - 1; i >= 0; i-- {
+		width, height := text.Measure(labels[i], dashboard.face, 0)
+		x -= width + 2*buttonPadding
+		buttons[i] = button{
+			label: labels[i],
+			box: area{
+				x:      float32(x),
+				y:      float32(dashboard.screenHeight - footerHeight + 4),
+				width:  float32(width + 2*buttonPadding),
+				height: float32(height + 12),
+			},
+			click: clicks[i],
+		}
+		x -= buttonSpacing
+	}
+	return buttons
+}
+
+// Click presses the button at the position and returns what the game has to do for it.
+func (dashboard *Dashboard) Click(x, y int) Command {
+	for _, button := range dashboard.buttons() {
+		box := button.box
+		if float32(x) >= box.x && float32(x) < box.x+box.width && float32(y) >= box.y && float32(y) < box.y+box.height {
+			return button.click()
+		}
+	}
+	return NoCommand
+}
+
+func (dashboard *Dashboard) drawButtons(screen *ebiten.Image) {
+	for _, button := range dashboard.buttons() {
+		box := button.box
+		vector.DrawFilledRect(screen, box.x, box.y, box.width, box.height, panelColor, false)
+		vector.StrokeRect(screen, box.x, box.y, box.width, box.height, 1, axisColor, false)
+		dashboard.drawText(screen, button.label, float64(box.x)+buttonPadding, float64(box.y)+6)
+	}
+}
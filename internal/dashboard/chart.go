@@ -0,0 +1,154 @@
+package dashboard
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	titleHeight = 36
+	axisWidth   = 70 // room for the values left of the plot
+	labelHeight = 30 // room for the labels under the plot
+)
+
+var (
+	panelColor = color.RGBA{R: 40, G: 46, B: 60, A: 255}
+	gridColor  = color.RGBA{R: 255, G: 255, B: 255, A: 40}
+	axisColor  = color.RGBA{R: 200, G: 200, B: 200, A: 255}
+)
+
+type area struct {
+	x, y, width, height float32
+}
+
+// drawPanel draws the background and the title of a chart and returns the area left for the plot.
+func (dashboard *Dashboard) drawPanel(screen *ebiten.Image, box area, title string) area {
+	vector.DrawFilledRect(screen, box.x, box.y, box.width, box.height, panelColor, false)
+	dashboard.drawText(screen, title, float64(box.x)+12, float64(box.y)+6)
+	return area{
+		x:      box.x + axisWidth,
+		y:      box.y + titleHeight + 10,
+		width:  box.width - axisWidth - 20,
+		height: box.height - titleHeight - labelHeight - 20,
+	}
+}
+
+// drawAxis draws the grid of the plot with the values from 0 to top.
+func (dashboard *Dashboard) drawAxis(screen *ebiten.Image, plot area, top float64) {
+	for i := range 5 {
+		y := plot.y + plot.height*float32(i)/4
+		vector.StrokeLine(screen, plot.x, y, plot.x+plot.width, y, 1, gridColor, false)
+		dashboard.drawText(screen, formatValue(top*float64(4-i)/4), float64(plot.x-axisWidth)+8, float64(y)-12)
+	}
+	vector.StrokeLine(screen, plot.x, plot.y, plot.x, plot.y+plot.height, 2, axisColor, false)
+	vector.StrokeLine(screen, plot.x, plot.y+plot.height, plot.x+plot.width, plot.y+plot.height, 2, axisColor, false)
+}
+
+// drawLine draws the values from left to right joined by a line.
+func (dashboard *Dashboard) drawLine(screen *ebiten.Image, plot area, values []float64, clr color.Color) {
+	top := niceTop(values)
+	dashboard.drawAxis(screen, plot, top)
+	if len(values) == 0 {
+		dashboard.drawEmpty(screen, plot)
+		return
+	}
+	point := func(i int) (float32, float32) {
+		x := plot.x + plot.width/2
+		if len(values) > 1 {
+			x = plot.x + plot.width*float32(i)/float32(len(values)-1)
+		}
+		return x, plot.y + plot.height - plot.height*float32(values[i]/top)
+	}
+	for i := 1; i < len(values); i++ {
+		x1, y1 := point(i - 1)
+		x2, y2 := point(i)
+		vector.StrokeLine(screen, x1, y1, x2, y2, 2, clr, true)
+	}
+	if len(values) <= 50 { // dots would cover the line of a long history
+		for i := range values {
+			x, y := point(i)
+			vector.DrawFilledCircle(screen, x, y, 4, clr, true)
+		}
+	}
+}
+
+// drawBars draws a bar for every value with its label under it, labels that wouldn't fit are skipped.
+func (dashboard *Dashboard) drawBars(screen *ebiten.Image, plot area, labels []string, values []float64, clr color.Color) {
+	top := niceTop(values)
+	dashboard.drawAxis(screen, plot, top)
+	if len(values) == 0 {
+		dashboard.drawEmpty(screen, plot)
+		return
+	}
+	slot := plot.width / float32(len(values))
+	var widest float64
+	for _, label := range labels {
+		width, _ := text.Measure(label, dashboard.face, 0)
+		widest = max(widest, width)
+	}
+	every := max(int(math.Ceil((widest+10)/float64(slot))), 1)
+	for i, value := range values {
+		height := plot.height * float32(value/top)
+		x := plot.x + slot*float32(i)
+		vector.DrawFilledRect(screen, x+slot*0.15, plot.y+plot.height-height, slot*0.7, height, clr, false)
+		if i < len(labels) && i%every == 0 {
+			dashboard.drawBelow(screen, plot, labels[i], (float64(i)+0.5)/float64(len(values)))
+		}
+	}
+}
+
+func (dashboard *Dashboard) drawEmpty(screen *ebiten.Image, plot area) {
+	dashboard.drawCentered(screen, "No runs", float64(plot.x+plot.width/2), float64(plot.y+plot.height/2)-12)
+}
+
+// drawBelow draws the label under the plot at the share of its width.
+func (dashboard *Dashboard) drawBelow(screen *ebiten.Image, plot area, label string, at float64) {
+	width, _ := text.Measure(label, dashboard.face, 0)
+	x := float64(plot.x) + float64(plot.width)*at - width/2
+	x = max(min(x, float64(plot.x+plot.width)-width), float64(plot.x)) // the first and the last label stay in the panel
+	dashboard.drawText(screen, label, x, float64(plot.y+plot.height)+6)
+}
+
+func (dashboard *Dashboard) drawCentered(screen *ebiten.Image, label string, x, y float64) {
+	width, _ := text.Measure(label, dashboard.face, 0)
+	dashboard.drawText(screen, label, x-width/2, y)
+}
+
+func (dashboard *Dashboard) drawText(screen *ebiten.Image, label string, x, y float64) {
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(x, y)
+	text.Draw(screen, label, dashboard.face, op)
+}
+
+// niceTop rounds the largest value up to 1, 2 or 5 times a power of ten, so the grid lines have round values.
+func niceTop(values []float64) float64 {
+	var top float64
+	for _, value := range values {
+		top = max(top, value)
+	}
+	if top <= 0 {
+		return 1
+	}
+	step := math.Pow(10, math.Floor(math.Log10(top)))
+	for _, factor := range []float64{1, 2, 5, 10} {
+		if top <= factor*step {
+			return factor * step
+		}
+	}
+	return 10 * step
+}
+
+func formatValue(value float64) string {
+	if value >= 10000 {
+		return fmt.Sprintf("%.0fk", value/1000)
+	}
+	if value == math.Trunc(value) {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.1f", value)
+}
@@ -0,0 +1,288 @@
+package dashboard
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+
+	"github.com/VxVxN/game/pkg/statisticer"
+)
+
+const (
+	margin        = 40
+	headerHeight  = 110
+	footerHeight  = 50
+	survivalBins  = 10
+	sessions      = 12               // sessions shown in the chart, the last ones
+	sessionPause  = 30 * time.Minute // a longer pause between runs starts the next session
+	everyMode     = "All modes"
+	everyProfile  = "All profiles"
+	finishedCause = "finished" // the mode ended the run, there was no crash
+)
+
+var (
+	backgroundColor = color.RGBA{R: 25, G: 30, B: 40, A: 255}
+	scoreColor      = color.RGBA{R: 250, G: 210, B: 30, A: 255}
+	survivalColor   = color.RGBA{R: 70, G: 140, B: 230, A: 255}
+	causeColor      = color.RGBA{R: 220, G: 40, B: 40, A: 255}
+	sessionColor    = color.RGBA{R: 40, G: 190, B: 70, A: 255}
+)
+
+// History is the run history of one profile in one mode.
+type History struct {
+	Mode    string
+	Profile string
+	Records []statisticer.Record
+}
+
+// Dashboard draws charts of the run history, filtered by mode and profile.
+type Dashboard struct {
+	histories    []History
+	modes        []string
+	profiles     []string
+	mode         int    // 0 is every mode, then the modes
+	profile      int    // 0 is every profile, then the profiles
+	charts       charts // of the runs that pass the filters
+	help         string // the controls shown in the footer
+	face         text.Face
+	screenWidth  float64
+	screenHeight float64
+}
+
+func New(face text.Face, screenWidth, screenHeight float64) *Dashboard {
+	return &Dashboard{
+		face:         face,
+		screenWidth:  screenWidth,
+		screenHeight: screenHeight,
+	}
+}
+
+// SetHelp sets the controls shown in the footer, e.g. after they are bound to other keys.
+func (dashboard *Dashboard) SetHelp(help string) {
+	dashboard.help = help
+}
+
+// SetHistories replaces the runs the charts are drawn from, the filters stay where they were if they still can.
+func (dashboard *Dashboard) SetHistories(histories []History) {
+	dashboard.histories = histories
+	dashboard.modes = dashboard.modes[:0]
+	dashboard.profiles = dashboard.profiles[:0]
+	for _, history := range histories {
+		if !slices.Contains(dashboard.modes, history.Mode) {
+			dashboard.modes = append(dashboard.modes, history.Mode)
+		}
+		if !slices.Contains(dashboard.profiles, history.Profile) {
+			dashboard.profiles = append(dashboard.profiles, history.Profile)
+		}
+	}
+	if dashboard.mode > len(dashboard.modes) {
+		dashboard.mode = 0
+	}
+	if dashboard.profile > len(dashboard.profiles) {
+		dashboard.profile = 0
+	}
+	dashboard.filter()
+}
+
+func (dashboard *Dashboard) SwitchMode(step int) {
+	options := len(dashboard.modes) + 1
+	dashboard.mode = (dashboard.mode + step + options) % options
+	dashboard.filter()
+}
+
+func (dashboard *Dashboard) SwitchProfile(step int) {
+	options := len(dashboard.profiles) + 1
+	dashboard.profile = (dashboard.profile + step + options) % options
+	dashboard.filter()
+}
+
+// Show filters the charts by the mode and the profile with the names.
+func (dashboard *Dashboard) Show(mode, profile string) {
+	dashboard.mode = slices.Index(dashboard.modes, mode) + 1
+	dashboard.profile = slices.Index(dashboard.profiles, profile) + 1
+	dashboard.filter()
+}
+
+func (dashboard *Dashboard) modeName() string {
+	if dashboard.mode == 0 {
+		return everyMode
+	}
+	return dashboard.modes[dashboard.mode-1]
+}
+
+func (dashboard *Dashboard) profileName() string {
+	if dashboard.profile == 0 {
+		return everyProfile
+	}
+	return dashboard.profiles[dashboard.profile-1]
+}
+
+// filter picks the runs of the mode and the profile, the runs of several histories are merged
+// in the order they were driven.
+func (dashboard *Dashboard) filter() {
+	var picked []History
+	for _, history := range dashboard.histories {
+		if (dashboard.mode == 0 || history.Mode == dashboard.modeName()) &&
+			(dashboard.profile == 0 || history.Profile == dashboard.profileName()) {
+			picked = append(picked, history)
+		}
+	}
+	if len(picked) == 1 {
+		dashboard.charts = newCharts(picked[0].Records)
+		return
+	}
+	var records []statisticer.Record
+	for _, history := range picked {
+		records = append(records, history.Records...)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+	dashboard.charts = newCharts(records)
+}
+
+func (dashboard *Dashboard) Draw(screen *ebiten.Image) {
+	screen.Fill(backgroundColor)
+
+	dashboard.drawText(screen, fmt.Sprintf("Statistics: %s, %s", dashboard.modeName(), dashboard.profileName()), margin, 20)
+	dashboard.drawText(screen, dashboard.charts.summary, margin, 56)
+	dashboard.drawText(screen, dashboard.help, margin, dashboard.screenHeight-footerHeight+10)
+	dashboard.drawButtons(screen)
+
+	width := float32(dashboard.screenWidth-3*margin) / 2
+	height := float32(dashboard.screenHeight-headerHeight-footerHeight-margin) / 2
+	left, right := float32(margin), float32(2*margin)+width
+	top, bottom := float32(headerHeight), float32(headerHeight+margin)+height
+
+	dashboard.drawScores(screen, area{x: left, y: top, width: width, height: height})
+	dashboard.drawBarChart(screen, area{x: right, y: top, width: width, height: height}, "Survival time", dashboard.charts.survival, survivalColor)
+	dashboard.drawBarChart(screen, area{x: left, y: bottom, width: width, height: height}, "Deaths by cause", dashboard.charts.causes, causeColor)
+	dashboard.drawBarChart(screen, area{x: right, y: bottom, width: width, height: height}, "Average points per session", dashboard.charts.sessions, sessionColor)
+}
+
+func (dashboard *Dashboard) drawBarChart(screen *ebiten.Image, box area, title string, chart bars, clr color.Color) {
+	plot := dashboard.drawPanel(screen, box, title)
+	dashboard.drawBars(screen, plot, chart.labels, chart.values, clr)
+}
+
+func (dashboard *Dashboard) drawScores(screen *ebiten.Image, box area) {
+	plot := dashboard.drawPanel(screen, box, "Score over time")
+	dashboard.drawLine(screen, plot, dashboard.charts.scores, scoreColor)
+	if len(dashboard.charts.scores) > 0 {
+		dashboard.drawBelow(screen, plot, dashboard.charts.firstRun, 0)
+		dashboard.drawBelow(screen, plot, dashboard.charts.lastRun, 1)
+	}
+}
+
+// charts are what is drawn of the runs that pass the filters, they are worked out once when the filters change.
+type charts struct {
+	summary           string
+	scores            []float64
+	firstRun, lastRun string
+	survival          bars
+	causes            bars
+	sessions          bars
+}
+
+type bars struct {
+	labels []string
+	values []float64
+}
+
+func newCharts(records []statisticer.Record) charts {
+	charts := charts{
+		summary:  "No runs yet",
+		scores:   make([]float64, len(records)),
+		survival: survival(records),
+		causes:   causes(records),
+		sessions: sessionAverages(records),
+	}
+	for i, record := range records {
+		charts.scores[i] = float64(record.Points)
+	}
+	if len(records) > 0 {
+		average := statisticer.Average(records)
+		current, longest := statisticer.DayStreaks(records, time.Now())
+		charts.summary = fmt.Sprintf("Runs: %d   Best: %d   Average: %.0f points, %.0f m   Days in a row: %d (longest %d)",
+			average.Runs, statisticer.Top(records, 1)[0].Points, average.Points, average.Distance, current, longest)
+		charts.firstRun = runDate(records[0])
+		charts.lastRun = runDate(records[len(records)-1])
+	}
+	return charts
+}
+
+func survival(records []statisticer.Record) bars {
+	var longest time.Duration
+	for _, record := range records {
+		longest = max(longest, record.Duration)
+	}
+	if longest == 0 {
+		return bars{}
+	}
+	bin := time.Duration(math.Ceil(longest.Seconds()/survivalBins)) * time.Second
+	counts := make([]float64, survivalBins)
+	labels := make([]string, survivalBins)
+	for i := range labels {
+		labels[i] = fmt.Sprintf("%ds", int((time.Duration(i) * bin).Seconds()))
+	}
+	for _, record := range records {
+		if record.Duration > 0 {
+			counts[min(int(record.Duration/bin), survivalBins-1)]++
+		}
+	}
+	return bars{labels: labels, values: counts}
+}
+
+func causes(records []statisticer.Record) bars {
+	counts := make(map[string]float64)
+	for _, record := range records {
+		if record.Time.IsZero() {
+			continue // the cause wasn't kept before the history
+		}
+		cause := record.Cause
+		if cause == "" {
+			cause = finishedCause
+		}
+		counts[cause]++
+	}
+	var causes []string
+	for cause := range counts {
+		causes = append(causes, cause)
+	}
+	sort.Slice(causes, func(i, j int) bool {
+		if counts[causes[i]] != counts[causes[j]] {
+			return counts[causes[i]] > counts[causes[j]]
+		}
+		return causes[i] < causes[j]
+	})
+	values := make([]float64, len(causes))
+	for i, cause := range causes {
+		values[i] = counts[cause]
+	}
+	return bars{labels: causes, values: values}
+}
+
+func sessionAverages(records []statisticer.Record) bars {
+	all := statisticer.Sessions(records, sessionPause)
+	all = all[max(len(all)-sessions, 0):]
+	labels := make([]string, len(all))
+	values := make([]float64, len(all))
+	for i, session := range all {
+		labels[i] = session[0].Time.Local().Format("Jan 2")
+		values[i] = statisticer.Average(session).Points
+	}
+	return bars{labels: labels, values: values}
+}
+
+func runDate(record statisticer.Record) string {
+	if record.Time.IsZero() {
+		return "before the history"
+	}
+	return record.Time.Local().Format("Jan 2 15:04")
+}
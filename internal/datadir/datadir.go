@@ -0,0 +1,161 @@
+package datadir
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const appName = "racer"
+
+var (
+	// configFiles are kept for good: the settings, the profiles with their records and the campaign progress.
+	// statistics* are the records from before the profiles, the game moves them to the default profile.
+	configFiles = []string{"settings.json", "profiles.json", "profiles", "progress.json", "leaderboard_queue.json", "statistics*"}
+	// cacheFiles can be lost without losing anything the player has done
+	cacheFiles = []string{"racer.log", "last_replay.json"}
+)
+
+// Dir is where the files of the player are kept, so the game finds them whichever directory it is started from.
+type Dir struct {
+	config string
+	cache  string
+}
+
+// New returns the directories of the user for the game, or the directory at path for all the files if it isn't empty.
+func New(path string) (Dir, error) {
+	if path != "" {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			return Dir{}, err
+		}
+		return Dir{config: path, cache: filepath.Join(path, "cache")}, nil
+	}
+	config, err := os.UserConfigDir()
+	if err != nil {
+		return Dir{}, fmt.Errorf("failed to find the config directory, choose one with --data-dir: %v", err)
+	}
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return Dir{}, fmt.Errorf("failed to find the cache directory, choose one with --data-dir: %v", err)
+	}
+	return Dir{config: filepath.Join(config, appName), cache: filepath.Join(cache, appName)}, nil
+}
+
+// Create makes the directories if they don't exist yet.
+func (dir Dir) Create() error {
+	for _, path := range []string{dir.config, dir.cache} {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return fmt.Errorf("failed to create data directory: %v", err)
+		}
+	}
+	return nil
+}
+
+// Path returns the path of a file that is kept for good.
+func (dir Dir) Path(name string) string {
+	return filepath.Join(dir.config, name)
+}
+
+// CachePath returns the path of a file that can be lost, e.g. the log.
+func (dir Dir) CachePath(name string) string {
+	return filepath.Join(dir.cache, name)
+}
+
+func (dir Dir) String() string {
+	return dir.config
+}
+
+// Migrate moves the files the game kept in the working directory before there was a data directory and returns
+// the ones it has moved. It only does it on the first start, before the game has made its profiles.
+func (dir Dir) Migrate(workingDir string) ([]string, error) {
+	if same(workingDir, dir.config) {
+		return nil, nil
+	}
+	if _, err := os.Stat(dir.Path("profiles.json")); !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	var moved []string
+	for _, files := range []struct {
+		patterns []string
+		to       func(name string) string
+	}{
+		{configFiles, dir.Path},
+		{cacheFiles, dir.CachePath},
+	} {
+		for _, pattern := range files.patterns {
+			paths, err := filepath.Glob(filepath.Join(workingDir, pattern))
+			if err != nil {
+				return moved, err
+			}
+			for _, path := range paths {
+				to := files.to(filepath.Base(path))
+				if _, err = os.Stat(to); !errors.Is(err, os.ErrNotExist) {
+					continue // never overwrite what is in the data directory already
+				}
+				if err = move(path, to); err != nil {
+					return moved, fmt.Errorf("failed to move %s to the data directory: %v", path, err)
+				}
+				moved = append(moved, filepath.Base(path))
+			}
+		}
+	}
+	return moved, nil
+}
+
+func same(first, second string) bool {
+	firstInfo, err := os.Stat(first)
+	if err != nil {
+		return false
+	}
+	secondInfo, err := os.Stat(second)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(firstInfo, secondInfo)
+}
+
+// move renames the file or the directory, or copies it if it is on another drive.
+func move(from, to string) error {
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+	err := filepath.WalkDir(from, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, relative)
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		return copyFile(path, target)
+	})
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(from)
+}
+
+func copyFile(from, to string) error {
+	source, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	target, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(target, source); err != nil {
+		target.Close()
+		return err
+	}
+	return target.Close()
+}
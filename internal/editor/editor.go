@@ -0,0 +1,399 @@
+package editor
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/VxVxN/game/internal/campaign"
+	"github.com/VxVxN/game/internal/traffic"
+	"github.com/VxVxN/game/pkg/background"
+)
+
+const (
+	lanes      = 5
+	laneWidth  = 200
+	firstLane  = 20  // the shoulder of the road image
+	viewMetres = 600 // metres of road visible at once
+	snapMetres = 10
+	scrubSpeed = 4 // metres per tick while Up or Down is held
+	itemMetres = 6
+)
+
+var vehicleMetres = map[traffic.Vehicle]float64{
+	traffic.VehicleCar:       21,
+	traffic.VehicleTruck:     26,
+	traffic.VehicleLongTruck: 42.5,
+}
+
+type brush struct {
+	name    string
+	vehicle traffic.Vehicle
+	hazard  string
+	pickup  string
+	color   color.Color
+}
+
+var brushes = []brush{
+	{name: "Car", vehicle: traffic.VehicleCar, color: color.RGBA{R: 70, G: 140, B: 230, A: 255}},
+	{name: "Truck", vehicle: traffic.VehicleTruck, color: color.RGBA{R: 40, G: 90, B: 180, A: 255}},
+	{name: "Long truck", vehicle: traffic.VehicleLongTruck, color: color.RGBA{R: 20, G: 50, B: 130, A: 255}},
+	{name: "Cone", hazard: "cone", color: color.RGBA{R: 245, G: 120, B: 20, A: 255}},
+	{name: "Barrier", hazard: "barrier", color: color.RGBA{R: 220, G: 40, B: 40, A: 255}},
+	{name: "Pothole", hazard: "pothole", color: color.RGBA{R: 110, G: 90, B: 70, A: 255}},
+	{name: "Oil slick", hazard: "oil_slick", color: color.RGBA{R: 60, G: 40, B: 100, A: 255}},
+	{name: "Debris", hazard: "debris", color: color.RGBA{R: 140, G: 140, B: 140, A: 255}},
+	{name: "Fuel can", pickup: "fuel_can", color: color.RGBA{R: 40, G: 190, B: 70, A: 255}},
+}
+
+// mark is something drawn on the timeline, from and to are metres.
+type mark struct {
+	lane     int
+	from, to float64
+	label    string
+	color    color.Color
+	remove   func()
+}
+
+// Editor shows a level as a timeline of its lanes, the bottom of the screen is behind the playhead
+// and the road ahead goes up like in the game. Copies of the levels are edited, the campaign only
+// gets a level once it has been saved.
+type Editor struct {
+	levels       []*campaign.Level // the levels the campaign plays
+	drafts       []*campaign.Level
+	dirty        []bool
+	level        int
+	brush        int
+	lane         int
+	playhead     float64
+	screenWidth  float64
+	screenHeight float64
+	startRoad    float64
+	face         text.Face
+	message      string
+	onTestPlay   func(level *campaign.Level, from float64)
+}
+
+func New(levels []*campaign.Level, face text.Face, screenWidth, screenHeight, startRoad float64) *Editor {
+	drafts := make([]*campaign.Level, len(levels))
+	for i, level := range levels {
+		drafts[i] = level.Clone()
+	}
+	return &Editor{
+		levels:       levels,
+		drafts:       drafts,
+		dirty:        make([]bool, len(levels)),
+		screenWidth:  screenWidth,
+		screenHeight: screenHeight,
+		startRoad:    startRoad,
+		face:         face,
+		lane:         2,
+	}
+}
+
+// SetOnTestPlay sets what happens when the designer wants to drive the level from the playhead.
+func (editor *Editor) SetOnTestPlay(onTestPlay func(level *campaign.Level, from float64)) {
+	editor.onTestPlay = onTestPlay
+}
+
+func (editor *Editor) current() *campaign.Level {
+	if len(editor.drafts) == 0 {
+		return nil
+	}
+	return editor.drafts[editor.level]
+}
+
+func (editor *Editor) Update() {
+	level := editor.current()
+	if level == nil {
+		return
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyUp) {
+		editor.playhead += scrubSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyDown) {
+		editor.playhead -= scrubSpeed
+	}
+	_, wheel := ebiten.Wheel()
+	editor.playhead += wheel * 5 * snapMetres
+	if inpututil.IsKeyJustPressed(ebiten.KeyHome) {
+		editor.playhead = 0
+	}
+	editor.playhead = math.Max(0, math.Min(editor.playhead, level.Length))
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
+		editor.lane = max(editor.lane-1, 0)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+		editor.lane = min(editor.lane+1, lanes-1)
+	}
+	for i := range brushes {
+		if inpututil.IsKeyJustPressed(ebiten.Key1 + ebiten.Key(i)) {
+			editor.brush = i
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		editor.level = (editor.level + 1) % len(editor.drafts)
+		editor.playhead = 0
+		editor.message = ""
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		editor.place(editor.lane, snap(editor.playhead))
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyDelete) || inpututil.IsKeyJustPressed(ebiten.KeyBackspace) {
+		editor.remove(editor.lane, editor.playhead)
+	}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		x, y := ebiten.CursorPosition()
+		if lane, ok := editor.laneAt(float64(x)); ok {
+			at := editor.metresAt(float64(y))
+			if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+				editor.place(lane, snap(at))
+			} else {
+				editor.remove(lane, at)
+			}
+		}
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyS) {
+		editor.save()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyP) && editor.onTestPlay != nil {
+		editor.testPlay()
+	}
+}
+
+// testPlay drives the level as it is edited, but only by the rules a saved script has to follow.
+func (editor *Editor) testPlay() {
+	level := editor.current()
+	if level.Script != nil {
+		if err := level.Script.Validate(); err != nil {
+			editor.message = fmt.Sprintf("Can't test-play: %v", err)
+			return
+		}
+	}
+	editor.onTestPlay(level, snap(editor.playhead))
+}
+
+func snap(metres float64) float64 {
+	return math.Max(0, math.Round(metres/snapMetres)*snapMetres)
+}
+
+func (editor *Editor) save() {
+	draft := editor.current()
+	if err := draft.Save(); err != nil {
+		editor.message = fmt.Sprintf("Failed to save: %v", err)
+		return
+	}
+	// copied into the level the campaign has, so everything holding it sees the saved one
+	*editor.levels[editor.level] = *draft.Clone()
+	editor.dirty[editor.level] = false
+	editor.message = "Saved"
+}
+
+func (editor *Editor) script() *traffic.Script {
+	level := editor.current()
+	if level.Script == nil {
+		level.Script = &traffic.Script{}
+	}
+	return level.Script
+}
+
+func (editor *Editor) place(lane int, at float64) {
+	brush := brushes[editor.brush]
+	script := editor.script()
+	switch {
+	case brush.vehicle != "":
+		script.Waves = append(script.Waves, &traffic.Wave{At: at, Pattern: traffic.PatternWall, Vehicle: brush.vehicle, Lanes: []int{lane + 1}})
+	case brush.hazard != "":
+		script.Hazards = append(script.Hazards, &traffic.Item{At: at, Lane: lane + 1, Kind: brush.hazard})
+	case brush.pickup != "":
+		script.Pickups = append(script.Pickups, &traffic.Item{At: at, Lane: lane + 1, Kind: brush.pickup})
+	}
+	editor.dirty[editor.level] = true
+	editor.message = ""
+}
+
+// remove deletes what covers the point, a patterned wave is removed as a whole.
+func (editor *Editor) remove(lane int, at float64) {
+	for _, mark := range editor.marks() {
+		if mark.lane == lane && at >= mark.from && at <= mark.to {
+			mark.remove()
+			editor.dirty[editor.level] = true
+			editor.message = ""
+			return
+		}
+	}
+}
+
+func (editor *Editor) marks() []mark {
+	script := editor.current().Script
+	if script == nil {
+		return nil
+	}
+	var marks []mark
+	for _, wave := range script.Waves {
+		for _, placement := range wave.Placements() {
+			from := wave.At + placement.Offset/background.PixelsPerMetre
+			marks = append(marks, mark{
+				lane:  placement.Lane,
+				from:  from,
+				to:    from + vehicleMetres[wave.Vehicle],
+				label: string(wave.Vehicle),
+				color: brushFor(func(brush brush) bool { return brush.vehicle == wave.Vehicle }).color,
+				remove: func() {
+					script.Waves = slices.DeleteFunc(script.Waves, func(w *traffic.Wave) bool { return w == wave })
+				},
+			})
+		}
+	}
+	for _, item := range script.Hazards {
+		marks = append(marks, mark{
+			lane:  item.Lane - 1,
+			from:  item.At,
+			to:    item.At + itemMetres,
+			label: item.Kind,
+			color: brushFor(func(brush brush) bool { return brush.hazard == item.Kind }).color,
+			remove: func() {
+				script.Hazards = slices.DeleteFunc(script.Hazards, func(i *traffic.Item) bool { return i == item })
+			},
+		})
+	}
+	for _, item := range script.Pickups {
+		marks = append(marks, mark{
+			lane:  item.Lane - 1,
+			from:  item.At,
+			to:    item.At + itemMetres,
+			label: item.Kind,
+			color: brushFor(func(brush brush) bool { return brush.pickup == item.Kind }).color,
+			remove: func() {
+				script.Pickups = slices.DeleteFunc(script.Pickups, func(i *traffic.Item) bool { return i == item })
+			},
+		})
+	}
+	return marks
+}
+
+func brushFor(match func(brush brush) bool) brush {
+	for _, brush := range brushes {
+		if match(brush) {
+			return brush
+		}
+	}
+	return brush{color: color.White}
+}
+
+func (editor *Editor) scale() float64 {
+	return editor.screenHeight / viewMetres
+}
+
+func (editor *Editor) playheadY() float64 {
+	return editor.screenHeight * 0.75
+}
+
+func (editor *Editor) yAt(metres float64) float64 {
+	return editor.playheadY() - (metres-editor.playhead)*editor.scale()
+}
+
+func (editor *Editor) metresAt(y float64) float64 {
+	return editor.playhead + (editor.playheadY()-y)/editor.scale()
+}
+
+func (editor *Editor) laneX(lane int) float64 {
+	return editor.startRoad + firstLane + float64(lane*laneWidth)
+}
+
+func (editor *Editor) laneAt(x float64) (int, bool) {
+	lane := int(math.Floor((x - editor.startRoad - firstLane) / laneWidth))
+	return lane, lane >= 0 && lane < lanes
+}
+
+func (editor *Editor) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{R: 60, G: 110, B: 50, A: 255})
+	level := editor.current()
+	if level == nil {
+		editor.drawText(screen, "There are no levels to edit", 20, 20)
+		return
+	}
+
+	roadX, roadWidth := float32(editor.laneX(0)), float32(lanes*laneWidth)
+	vector.DrawFilledRect(screen, roadX, 0, roadWidth, float32(editor.screenHeight), color.RGBA{R: 70, G: 70, B: 75, A: 255}, false)
+	for lane := 1; lane < lanes; lane++ {
+		x := float32(editor.laneX(lane))
+		vector.StrokeLine(screen, x, 0, x, float32(editor.screenHeight), 2, color.RGBA{R: 200, G: 200, B: 200, A: 120}, false)
+	}
+	x := float32(editor.laneX(editor.lane))
+	vector.DrawFilledRect(screen, x, 0, laneWidth, float32(editor.screenHeight), color.RGBA{R: 255, G: 255, B: 255, A: 20}, false)
+
+	// a line every 50 metres, labelled every 100
+	first := math.Floor(editor.metresAt(editor.screenHeight)/50) * 50
+	for metres := math.Max(first, 0); metres <= editor.metresAt(0); metres += 50 {
+		y := float32(editor.yAt(metres))
+		vector.StrokeLine(screen, roadX, y, roadX+roadWidth, y, 1, color.RGBA{R: 255, G: 255, B: 255, A: 50}, false)
+		if int(metres)%100 == 0 {
+			editor.drawText(screen, fmt.Sprintf("%d m", int(metres)), float64(roadX+roadWidth)+10, float64(y)-12)
+		}
+	}
+	finish := float32(editor.yAt(level.Length))
+	vector.StrokeLine(screen, roadX, finish, roadX+roadWidth, finish, 6, color.White, false)
+	editor.drawText(screen, "Finish", float64(roadX+roadWidth)+10, float64(finish)-40)
+
+	for _, mark := range editor.marks() {
+		top, bottom := editor.yAt(mark.to), editor.yAt(mark.from)
+		if bottom < 0 || top > editor.screenHeight {
+			continue
+		}
+		x := float32(editor.laneX(mark.lane)) + 30
+		vector.DrawFilledRect(screen, x, float32(top), laneWidth-60, float32(bottom-top), mark.color, false)
+		editor.drawText(screen, mark.label, float64(x)+4, top)
+	}
+
+	playhead := float32(editor.playheadY())
+	vector.StrokeLine(screen, roadX, playhead, roadX+roadWidth, playhead, 3, color.RGBA{R: 250, G: 210, B: 30, A: 255}, false)
+
+	unsaved := ""
+	if editor.dirty[editor.level] {
+		unsaved = " (unsaved)"
+	}
+	lines := []string{
+		fmt.Sprintf("%s%s", level.Name, unsaved),
+		fmt.Sprintf("Playhead: %d m / %d m", int(editor.playhead), int(level.Length)),
+		"",
+	}
+	for i, brush := range brushes {
+		prefix := "  "
+		if i == editor.brush {
+			prefix = "> "
+		}
+		lines = append(lines, fmt.Sprintf("%s%d. %s", prefix, i+1, brush.name))
+	}
+	lines = append(lines, "",
+		"Up/Down, wheel: scrub",
+		"Left/Right: lane",
+		"Space, left click: place",
+		"Delete, right click: remove",
+		"Tab: next level",
+		"P: test-play from the playhead",
+		"S: save",
+		"Escape: exit",
+		"",
+		editor.message,
+	)
+	for i, line := range lines {
+		editor.drawText(screen, line, 20, 20+float64(i)*26)
+	}
+}
+
+func (editor *Editor) drawText(screen *ebiten.Image, label string, x, y float64) {
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(x, y)
+	text.Draw(screen, label, editor.face, op)
+}
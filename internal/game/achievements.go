@@ -0,0 +1,77 @@
+package game
+
+import (
+	"image/color"
+	"path/filepath"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/VxVxN/game/internal/achievements"
+	"github.com/VxVxN/game/internal/profile"
+)
+
+const toastTicks = 3 * ebiten.DefaultTPS
+
+// toast is a message shown over the road for a while, they are shown one after another.
+type toast struct {
+	text  string
+	ticks int
+}
+
+func (game *Game) loadAchievements(player *profile.Profile) {
+	tracker, err := achievements.Load(filepath.Join(game.profiles.Dir(player), "achievements.json"))
+	if err != nil {
+		game.logger.Error("Failed to load achievements", "profile", player.ID, "error", err)
+	}
+	game.achievements = tracker
+}
+
+// achieve passes the event to the achievements of the profile and shows the ones it has unlocked.
+// A replay that is verified doesn't unlock anything.
+func (game *Game) achieve(event achievements.Event) {
+	if game.verifying != nil {
+		return
+	}
+	unlocked := game.achievements.Handle(event, time.Now())
+	for _, achievement := range unlocked {
+		game.logger.Info("Achievement unlocked", "achievement", achievement.ID)
+		game.toasts = append(game.toasts, toast{text: "Achievement unlocked: " + achievement.Name, ticks: toastTicks})
+	}
+	if len(unlocked) == 0 && event.Kind != achievements.RunEndEvent {
+		return
+	}
+	if err := game.achievements.Save(); err != nil {
+		game.logger.Error("Failed to save achievements", "error", err)
+	}
+}
+
+func (game *Game) updateToasts() {
+	if len(game.toasts) == 0 {
+		return
+	}
+	game.toasts[0].ticks--
+	if game.toasts[0].ticks <= 0 {
+		game.toasts = game.toasts[1:]
+	}
+}
+
+func (game *Game) drawToast(screen *ebiten.Image) {
+	if len(game.toasts) == 0 {
+		return
+	}
+	textFace := &text.GoTextFace{
+		Source: game.textFaceSource,
+		Size:   24,
+	}
+	width, height := text.Measure(game.toasts[0].text, textFace, 0)
+	x, y := game.windowWidth-width-60, 80.0
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width+40), float32(height+24), color.RGBA{R: 20, G: 20, B: 20, A: 200}, false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(width+40), float32(height+24), 2, color.RGBA{R: 250, G: 210, B: 30, A: 255}, false)
+
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(x+20, y+12)
+	text.Draw(screen, game.toasts[0].text, textFace, op)
+}
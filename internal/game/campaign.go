@@ -0,0 +1,111 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+
+	"github.com/VxVxN/game/internal/campaign"
+	"github.com/VxVxN/game/internal/input"
+)
+
+const fuelCanPoints = 20
+
+type campaignMode struct {
+	level  *campaign.Level
+	result string
+	// a test play is started from the editor at a distance and doesn't count for the unlocks
+	testPlay bool
+	from     float64
+}
+
+func (mode *campaignMode) ID() string {
+	return "campaign_" + mode.level.ID
+}
+
+func (mode *campaignMode) Name() string {
+	return mode.level.Name
+}
+
+func (mode *campaignMode) Setup(game *Game) {
+	mode.result = ""
+	game.biomes.Fix(mode.level.Biome)
+	game.trafficOverride = mode.level.Traffic
+	game.trafficScript = mode.level.Script
+	game.startDistance = mode.from
+	game.hazards.SetEnabled(mode.level.Hazards)
+	for _, objective := range mode.level.Objectives {
+		if objective.Kind == campaign.ObjectiveFuel {
+			game.pickups.SetEnabled(true)
+		}
+	}
+}
+
+func (mode *campaignMode) Update(game *Game) bool {
+	return false
+}
+
+func (mode *campaignMode) Collision(game *Game, cause string) bool {
+	mode.result = "Level failed"
+	return true
+}
+
+// Points rewards driving high on the screen, which is driving fast.
+func (mode *campaignMode) Points(game *Game) float64 {
+	return pointsPerTick * (2 - game.player.Y/game.windowHeight)
+}
+
+func (mode *campaignMode) Finished(game *Game) bool {
+	if game.background.Distance() < mode.level.Length {
+		return false
+	}
+	mode.complete(game)
+	return true
+}
+
+func (mode *campaignMode) complete(game *Game) {
+	if !mode.level.Cleared(game.campaignProgress()) {
+		mode.result = "Level failed"
+		return
+	}
+	stars := mode.level.Rating(int(game.player.Points()))
+	mode.result = fmt.Sprintf("Level cleared! Stars: %d/3", stars)
+	if mode.testPlay {
+		return
+	}
+	if !game.unlocks.SetStars(game.profile.ID, mode.level.ID, stars) {
+		return
+	}
+	if err := game.unlocks.Save(); err != nil {
+		game.logger.Error("Failed to save campaign progress", "error", err)
+	}
+}
+
+func (mode *campaignMode) DrawHUD(game *Game, screen *ebiten.Image, textFace text.Face) {
+	progress := game.campaignProgress()
+	y := 20.0
+	drawHUDText(screen, fmt.Sprintf("%s: %.1f / %.1f km", mode.level.Name, progress.Distance/1000, mode.level.Length/1000), textFace, 20, y)
+	for _, objective := range mode.level.Objectives {
+		y += 30
+		mark := "[ ]"
+		if objective.Met(progress) {
+			mark = "[x]"
+		}
+		drawHUDText(screen, mark+" "+objective.String(), textFace, 20, y)
+	}
+	if mode.result != "" {
+		drawHUDText(screen, mode.result, textFace, 20, y+40)
+	}
+	if mode.testPlay {
+		drawHUDText(screen, "Test play, press "+game.controlName(input.Pause)+" to return to the editor", textFace, 20, y+80)
+	}
+}
+
+func (game *Game) campaignProgress() campaign.Progress {
+	return campaign.Progress{
+		Distance:   game.background.Distance(),
+		FuelCans:   game.fuelCans,
+		NearMisses: game.nearMisses,
+	}
+}
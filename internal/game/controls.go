@@ -0,0 +1,73 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/VxVxN/game/internal/input"
+	"github.com/VxVxN/game/internal/stager"
+)
+
+// controlName returns what the action is bound to for the help texts, the key if it has one.
+func (game *Game) controlName(action input.Action) string {
+	bindings := game.settings.SavedSettings.Bindings[action]
+	for _, binding := range bindings {
+		if binding.Device == input.Keyboard {
+			return binding.Title()
+		}
+	}
+	if len(bindings) == 0 {
+		return "(not bound)"
+	}
+	return bindings[0].Title()
+}
+
+func (game *Game) songsHelp() string {
+	return fmt.Sprintf("Press %s to switch to the previous song\nPress %s to switch to the next song",
+		game.controlName(input.PreviousTrack), game.controlName(input.NextTrack))
+}
+
+// leaveSettings returns to the page the settings were opened from.
+func (game *Game) leaveSettings() {
+	game.stager.SetStage(game.settingsUI.from)
+}
+
+// leaveControls returns to the settings as they were left, with the changes that aren't saved yet.
+func (game *Game) leaveControls() {
+	game.eventManager.StopListening()
+	game.stager.RecoveryLastStage()
+	game.settingsUI.songsHelp.Label = game.songsHelp()
+}
+
+// listenForBinding binds the action to the next key, gamepad input or mouse button that is pressed, Escape cancels.
+// A binding of another action is only taken from it when it is pressed twice in a row, the second time
+// the conflict is known.
+func (game *Game) listenForBinding(action input.Action, conflict *input.Binding) {
+	game.eventManager.Listen(func(binding input.Binding) {
+		if binding == input.Key(ebiten.KeyEscape) {
+			game.controlsUI.status.Label = "Nothing has changed"
+			return
+		}
+		bindings := game.settings.SavedSettings.Bindings.Clone()
+		if other, ok := bindings.Conflict(action, binding); ok && (conflict == nil || *conflict != binding) {
+			game.controlsUI.status.Label = fmt.Sprintf("%s is bound to %s, press it again to bind it to %s instead",
+				binding.Title(), other.Title(), action.Title())
+			game.listenForBinding(action, &binding)
+			return
+		}
+		bindings.Bind(action, binding)
+		game.setBindings(bindings, fmt.Sprintf("%s: %s", action.Title(), bindings.Titles(action)))
+	})
+}
+
+// setBindings saves the bindings and shows them on the controls page with the status.
+func (game *Game) setBindings(bindings input.Bindings, status string) {
+	if err := game.settings.SetBindings(bindings); err != nil {
+		game.logger.Error("Failed to save the controls", "error", err)
+		status = "The controls can't be saved: " + err.Error()
+	}
+	game.eventManager.SetBindings(bindings)
+	game.changeUIByStage[stager.ControlsStage]()
+	game.controlsUI.status.Label = status
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"image/color"
 
+	"github.com/VxVxN/game/internal/input"
 	"github.com/VxVxN/game/internal/shadow"
 	"github.com/VxVxN/game/internal/stager"
 	"github.com/VxVxN/gamedevlib/raycasting"
@@ -28,11 +29,34 @@ func (game *Game) drawGameStage(screen *ebiten.Image) {
 			v := raycasting.RayVertices(game.player.X, game.player.Y, nextLine.X2, nextLine.Y2, line.X2, line.Y2)
 			game.nightImage.DrawTriangles(v, []uint16{0, 1, 2}, game.triangleImage, opt)
 		}
+		if game.chaserVisible() {
+			game.cutPoliceLights()
+		}
 	}
 
-	game.background.Draw(screen)
-	game.player.Draw(screen)
-	game.cars.Draw(screen)
+	game.worldImage.Clear()
+	game.background.Draw(game.worldImage)
+	game.scenery.Draw(game.worldImage)
+	game.hazards.Draw(game.worldImage)
+	game.pickups.Draw(game.worldImage)
+	game.player.Draw(game.worldImage)
+	if game.twoPlayers {
+		game.secondPlayer.Draw(game.worldImage)
+	}
+	for _, opponent := range game.opponents {
+		if !opponent.Dead() {
+			opponent.Draw(game.worldImage)
+		}
+	}
+	game.cars.Draw(game.worldImage)
+	if game.chaserVisible() {
+		game.chaser.Draw(game.worldImage)
+	}
+
+	worldOp := &ebiten.DrawImageOptions{}
+	worldOp.ColorScale = game.biomes.Grade()
+	screen.DrawImage(game.worldImage, worldOp)
+
 	textFace := &text.GoTextFace{
 		Source: game.textFaceSource,
 		Size:   24,
@@ -44,11 +68,22 @@ func (game *Game) drawGameStage(screen *ebiten.Image) {
 	op.LayoutOptions.PrimaryAlign = text.AlignCenter
 	text.Draw(screen, fmt.Sprintf("Points: %d", int(game.player.Points())), textFace, op)
 
+	op = &text.DrawOptions{}
+	op.GeoM.Translate(game.windowWidth/2, 30)
+	op.ColorScale.Scale(0, 0, 0, 1)
+	op.LayoutOptions.PrimaryAlign = text.AlignCenter
+	text.Draw(screen, "Biome: "+game.biomes.Current().Name, textFace, op)
+
 	if game.sunDirection == shadow.NotSun {
 		imageOp := &ebiten.DrawImageOptions{}
 		imageOp.ColorScale.ScaleAlpha(0.95)
 		screen.DrawImage(game.nightImage, imageOp)
+		if game.chaserVisible() {
+			game.drawPoliceGlow(screen)
+		}
 	}
+	game.mode.DrawHUD(game, screen, textFace)
+	game.drawTouchZones(screen)
 	game.explosionAnimation.Draw(screen)
 	if game.stager.Stage() == stager.GameOverStage {
 		textFace = &text.GoTextFace{
@@ -61,5 +96,17 @@ func (game *Game) drawGameStage(screen *ebiten.Image) {
 		op.ColorScale.Scale(255, 0, 0, 1)
 		op.LayoutOptions.PrimaryAlign = text.AlignCenter
 		text.Draw(screen, "Game over", textFace, op)
+
+		op = &text.DrawOptions{}
+		op.GeoM.Translate(game.windowWidth/2, game.windowHeight/2+80)
+		op.ColorScale.Scale(1, 0.82, 0.12, 1)
+		op.LayoutOptions.PrimaryAlign = text.AlignCenter
+		text.Draw(screen, fmt.Sprintf("+%d coins", game.run.coins), &text.GoTextFace{Source: game.textFaceSource, Size: 32}, op)
+
+		op = &text.DrawOptions{}
+		op.GeoM.Translate(game.windowWidth/2, game.windowHeight/2+140)
+		op.LayoutOptions.PrimaryAlign = text.AlignCenter
+		text.Draw(screen, "Press "+game.controlName(input.Confirm)+" or click to continue", &text.GoTextFace{Source: game.textFaceSource, Size: 24}, op)
 	}
+	game.drawToast(screen)
 }
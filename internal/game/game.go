@@ -2,18 +2,22 @@ package game
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
 	"log"
 	"log/slog"
+	"math/rand/v2"
 	"os"
 	"path"
-	"sort"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/VxVxN/gamedevlib/animation"
 	"github.com/VxVxN/gamedevlib/audioplayer"
-	"github.com/VxVxN/gamedevlib/eventmanager"
 	"github.com/VxVxN/gamedevlib/raycasting"
 	"github.com/VxVxN/gamedevlib/rectangle"
 	"github.com/ebitenui/ebitenui"
@@ -24,60 +28,181 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	"golang.org/x/image/font/gofont/goregular"
 
+	"github.com/VxVxN/game/internal/achievements"
+	"github.com/VxVxN/game/internal/biome"
+	"github.com/VxVxN/game/internal/campaign"
 	"github.com/VxVxN/game/internal/cargenerator"
+	"github.com/VxVxN/game/internal/dashboard"
+	"github.com/VxVxN/game/internal/datadir"
+	"github.com/VxVxN/game/internal/editor"
+	"github.com/VxVxN/game/internal/garage"
+	"github.com/VxVxN/game/internal/hazards"
+	"github.com/VxVxN/game/internal/input"
+	"github.com/VxVxN/game/internal/netplay"
+	"github.com/VxVxN/game/internal/pickups"
+	"github.com/VxVxN/game/internal/profile"
+	"github.com/VxVxN/game/internal/scenery"
 	"github.com/VxVxN/game/internal/settings"
 	"github.com/VxVxN/game/internal/shadow"
+	"github.com/VxVxN/game/internal/siren"
 	"github.com/VxVxN/game/internal/stager"
+	"github.com/VxVxN/game/internal/traffic"
 	"github.com/VxVxN/game/internal/ui"
 	"github.com/VxVxN/game/pkg/background"
+	"github.com/VxVxN/game/pkg/leaderboard"
 	playerpkg "github.com/VxVxN/game/pkg/player"
+	"github.com/VxVxN/game/pkg/replay"
 	"github.com/VxVxN/game/pkg/statisticer"
 )
 
-const sampleRate = 48000
+const (
+	sampleRate = 48000
+	// scriptedSpawnY is where scripted hazards and pickups enter, just above the top of the screen
+	scriptedSpawnY = -200
+)
 
 type Game struct {
 	// UI
-	resourcesUI       *ui.UiResources
-	mainMenuUI        *mainUI
-	menuUI            *menuUI
-	setPlayerRatingUI *setPlayerRatingUI
-	playerRatingsUI   *playerRatingsUI
-	settingsUI        *settingsUI
-	changeUIByStage   map[stager.Stage]func()
+	resourcesUI      *ui.UiResources
+	mainMenuUI       *mainUI
+	menuUI           *menuUI
+	profilesUI       *profilesUI
+	profileNameUI    *profileNameUI
+	achievementsUI   *achievementsUI
+	garageUI         *garageUI
+	shopUI           *shopUI
+	controlsUI       *controlsUI
+	playerRatingsUI  *playerRatingsUI
+	settingsUI       *settingsUI
+	modeSelectUI     *modeSelectUI
+	levelSelectUI    *levelSelectUI
+	lobbyUI          *lobbyUI
+	recoverRecordsUI *recoverRecordsUI
+	changeUIByStage  map[stager.Stage]func()
 
 	windowWidth, windowHeight  float64
 	startPlayerX, startPlayerY float64
 	scrollSpeed                float64
 	textFaceSource             *text.GoTextFaceSource
-	eventManager               *eventmanager.EventManager
+	eventManager               *input.EventManager
+	gamepads                   *input.Gamepads
 	player                     *playerpkg.Player
+	secondPlayer               *playerpkg.Player
+	vehicles                   []*garage.Vehicle
+	vehicleImages              map[string]vehicleImages
+	vehicle                    *garage.Vehicle // the one the player drives in the run
+	upgrades                   map[string]int  // the levels of the upgrades of the vehicle
+	twoPlayers                 bool
+	modeSteering               bool
+	opponents                  []*playerpkg.Player
+	opponentPool               [netplay.MaxPlayers - 1]*playerpkg.Player
+	spectating                 bool
+	session                    *netplay.Session
 	background                 *background.Background
+	scenery                    *scenery.Scenery
+	biomes                     *biome.Cycle
 	cars                       *cargenerator.CarGenerator
+	hazards                    *hazards.Spawner
+	pickups                    *pickups.Spawner
+	trafficOverride            *cargenerator.TrafficMix
+	trafficScript              *traffic.Script
+	waves                      *traffic.Runner
+	startDistance              float64
+	editor                     *editor.Editor
+	dashboard                  *dashboard.Dashboard
+	nearMisses                 int
+	fuelCans                   int
+	levels                     []*campaign.Level
+	unlocks                    *campaign.Unlocks
+	profiles                   *profile.Profiles
+	profile                    *profile.Profile
+	achievements               *achievements.Tracker
+	toasts                     []toast
+	renaming                   *profile.Profile // the profile the name page renames, a new one is created while nil
+	chaser                     *cargenerator.Chaser
+	siren                      *audio.Player
+	mode                       Mode
+	modes                      []Mode
+	ratingsMode                Mode
 	stager                     *stager.Stager
-	statisticer                *statisticer.Statisticer
+	statisticers               map[string]*statisticer.Statisticer
+	recovering                 recoverable
+	afterRecovery              func()
+	onlineLeaderboard          *leaderboard.Client
+	onlineTop                  onlineTop
+	fetchedTop                 *onlineTop
+	onlineMutex                sync.Mutex
 	audioPlayer                *audioplayer.AudioPlayer
+	worldImage                 *ebiten.Image
 	nightImage                 *ebiten.Image
 	triangleImage              *ebiten.Image
+	lightImage                 *ebiten.Image
 	objects                    []raycasting.Object
 	sunDirection               shadow.DirectionShadow
+	seed                       uint64
+	replay                     *replay.Replay
+	tickInput                  replay.Input
+	verifying                  *replay.Replay
+	watched                    []replay.Input // the inputs of the replay that is watched
+	watchTick                  int
+	fixedSeed                  *uint64 // every run is driven on it if it is set
+	run                        runStats
+	screenWidth, screenHeight  float64
 	explosionAnimation         *animation.Animation
 	logger                     *slog.Logger
 	settings                   *settings.Settings
 	loggerFile                 *os.File
+	dataDir                    datadir.Dir
 }
 
-func NewGame() (*Game, error) {
-	loggerFile, err := os.OpenFile("racer.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+// NewGame starts the game with the files of the player in the data directory. The first time the files
+// the game kept in the working directory before are moved to it.
+func NewGame(options Options) (*Game, error) {
+	dataDir := options.DataDir
+	if err := dataDir.Create(); err != nil {
+		return nil, err
+	}
+	workingDir, err := os.Getwd()
 	if err != nil {
-		log.Fatalf("error opening file: %v", err)
+		return nil, fmt.Errorf("can't get working dir: %s", err)
+	}
+	moved, err := dataDir.Migrate(workingDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate to the data directory: %v", err)
 	}
-
-	logger := slog.New(slog.NewJSONHandler(loggerFile, &slog.HandlerOptions{Level: slog.LevelDebug}))
-
 	w, h := ebiten.Monitor().Size()
-	logger.Info("Monitor size", "width", w, "height", h)
 	width, height := float64(w), float64(h)
+	if options.Replay != nil {
+		if err = options.Replay.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid replay: %v", err)
+		}
+		width, height = options.Replay.ScreenWidth, options.Replay.ScreenHeight
+	}
+	game, err := newGame(width, height, dataDir, options.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+	if len(moved) > 0 {
+		game.logger.Info("Moved the files from the working directory", "files", moved, "dir", dataDir.String())
+	}
+	if err = game.launch(options); err != nil {
+		game.Close()
+		return nil, err
+	}
+	return game, nil
+}
+
+// newGame lays the world out for a screen of the size, replays are verified on the size they were driven on.
+// The assets are found in the working directory and the files of the player in the data directory.
+func newGame(width, height float64, dataDir datadir.Dir, logLevel slog.Level) (*Game, error) {
+	loggerFile, err := os.OpenFile(dataDir.CachePath("racer.log"), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		log.Fatalf("error opening file: %v", err)
+	}
+
+	logger := slog.New(slog.NewJSONHandler(loggerFile, &slog.HandlerOptions{Level: logLevel}))
+	logger.Info("Monitor size", "width", width, "height", height)
+	logger.Info("Data directory", "dir", dataDir.String())
 
 	workingDir, err := os.Getwd()
 	if err != nil {
@@ -106,8 +231,6 @@ func NewGame() (*Game, error) {
 		return nil, fmt.Errorf("failed to init game explosion image: %v", err)
 	}
 
-	playerCar := gameElementsSet.SubImage(image.Rect(0, 450, 110, 650)).(*ebiten.Image)
-
 	greenCar := gameElementsSet.SubImage(image.Rect(0, 0, 110, 210)).(*ebiten.Image)
 	orangeCar := gameElementsSet.SubImage(image.Rect(120, 0, 230, 210)).(*ebiten.Image)
 	redCar := gameElementsSet.SubImage(image.Rect(240, 0, 350, 210)).(*ebiten.Image)
@@ -119,8 +242,15 @@ func NewGame() (*Game, error) {
 	blueLongTruck := gameElementsSet.SubImage(image.Rect(760, 0, 900, 425)).(*ebiten.Image)
 	greenLongTruck := gameElementsSet.SubImage(image.Rect(900, 0, 1030, 425)).(*ebiten.Image)
 
-	playerShadowImage := vehicleShadowsSet.SubImage(image.Rect(145, 250, 250, 450)).(*ebiten.Image)
-	playerShadow := shadow.New(playerShadowImage, shadow.NotSun)
+	trees := []*ebiten.Image{
+		gameElementsSet.SubImage(image.Rect(625, 552, 740, 659)).(*ebiten.Image),
+		gameElementsSet.SubImage(image.Rect(752, 552, 866, 650)).(*ebiten.Image),
+	}
+
+	barrier := gameElementsSet.SubImage(image.Rect(605, 270, 636, 462)).(*ebiten.Image)
+	pothole := gameElementsSet.SubImage(image.Rect(484, 486, 606, 610)).(*ebiten.Image)
+	police := gameElementsSet.SubImage(image.Rect(484, 262, 586, 466)).(*ebiten.Image)
+	fuelCan := gameElementsSet.SubImage(image.Rect(660, 268, 745, 368)).(*ebiten.Image)
 
 	carShadowImage := vehicleShadowsSet.SubImage(image.Rect(10, 0, 115, 195)).(*ebiten.Image)
 	carShadow := shadow.New(carShadowImage, shadow.NotSun)
@@ -143,26 +273,45 @@ func NewGame() (*Game, error) {
 	}
 	audioPlayer.Play()
 
+	sirenPlayer, err := siren.New(audioContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init siren: %v", err)
+	}
+
 	textFaceSource, err := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new face source: %v", err)
 	}
 
-	supportedKeys := []ebiten.Key{
-		ebiten.KeyUp,
-		ebiten.KeyDown,
-		ebiten.KeyLeft,
-		ebiten.KeyRight,
-		ebiten.KeyEscape,
-		ebiten.KeyEnter,
-		ebiten.KeyZ,
-		ebiten.KeyX,
+	gameSettings := settings.New(logger, dataDir.Path("settings.json"))
+
+	levels, err := campaign.LoadLevels(path.Join(workingDir, "levels"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load campaign levels: %v", err)
+	}
+
+	unlocks, progressErr := campaign.LoadUnlocks(dataDir.Path("progress.json"))
+	if errors.Is(progressErr, campaign.ErrCorrupt) {
+		logger.Error("Failed to load campaign progress", "error", progressErr) // the recovery page opens once the game is set up
+	} else if progressErr != nil {
+		return nil, fmt.Errorf("failed to load campaign progress: %v", progressErr)
+	}
+
+	profiles, err := loadProfiles(dataDir, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load profiles: %v", err)
+	}
+
+	vehicles, err := garage.Load(path.Join(workingDir, "vehicles"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load vehicles: %v", err)
 	}
 
-	gameSettings, err := settings.New(logger)
+	images, err := loadVehicleImages(assetPath, vehicles)
 	if err != nil {
-		return nil, fmt.Errorf("failed to init game settings: %v", err)
+		return nil, err
 	}
+	playerCar := images[garage.DefaultID]
 
 	explosionTileWidth := 900
 	explosionAnimation := animation.NewAnimation([]*ebiten.Image{
@@ -180,21 +329,68 @@ func NewGame() (*Game, error) {
 		scrollSpeed:  10.0,
 		windowWidth:  width,
 		windowHeight: height,
+		screenWidth:  width,
+		screenHeight: height,
 		background:   background.New(road, width),
+		scenery:      scenery.New(trees, &text.GoTextFace{Source: textFaceSource, Size: 14}, width, height, startRoad, float64(road.Bounds().Dx())),
 		//globalTime:         time.Now(),
-		eventManager:       eventmanager.NewEventManager(supportedKeys),
+		gamepads:           &input.Gamepads{},
 		textFaceSource:     textFaceSource,
 		stager:             stager.New(),
-		statisticer:        statisticer.NewStatisticer(path.Join(workingDir, "statistics.txt")),
 		audioPlayer:        audioPlayer,
+		biomes:             biome.NewCycle(biome.Defaults(road)),
+		worldImage:         ebiten.NewImage(int(width), int(height)),
 		nightImage:         ebiten.NewImage(int(width), int(height)),
 		triangleImage:      ebiten.NewImage(int(width), int(height)),
 		explosionAnimation: explosionAnimation,
 		cars:               cargenerator.New([]*ebiten.Image{greenCar, orangeCar, redCar, grayCar}, []*ebiten.Image{redTruck, greenTruck}, []*ebiten.Image{blueLongTruck, greenLongTruck}, height, startRoad, carShadow, truckShadow, longTruckShadow),
-		player:             playerpkg.NewPlayer(playerCar, playerShadow, gameSettings.SavedSettings.CarSensitivity),
+		hazards:            hazards.New(barrier, pothole, height, startRoad),
+		pickups:            pickups.New(fuelCan, height, startRoad),
+		levels:             levels,
+		unlocks:            unlocks,
+		profiles:           profiles,
+		chaser:             cargenerator.NewChaser(police, height, startRoad, shadow.New(carShadowImage, shadow.NotSun)),
+		siren:              sirenPlayer,
+		lightImage:         newLightImage(),
+		player:             playerpkg.NewPlayer(playerCar.sprite, shadow.New(playerCar.shadow, shadow.NotSun), gameSettings.SavedSettings.CarSensitivity),
+		secondPlayer:       playerpkg.NewPlayer(playerCar.sprite, shadow.New(playerCar.shadow, shadow.NotSun), gameSettings.SavedSettings.CarSensitivity),
+		vehicles:           vehicles,
+		vehicleImages:      images,
 		logger:             logger,
 		settings:           gameSettings,
 		loggerFile:         loggerFile,
+		dataDir:            dataDir,
+	}
+
+	game.eventManager = input.NewEventManager(gameSettings.SavedSettings.Bindings, game.gamepads)
+	game.editor = editor.New(levels, &text.GoTextFace{Source: textFaceSource, Size: 20}, width, height, startRoad)
+	game.dashboard = dashboard.New(&text.GoTextFace{Source: textFaceSource, Size: 20}, width, height)
+	game.editor.SetOnTestPlay(func(level *campaign.Level, from float64) {
+		game.StartMode(&campaignMode{level: level, testPlay: true, from: from})
+	})
+
+	var secondPlayerTint ebiten.ColorScale
+	secondPlayerTint.Scale(0.5, 0.8, 1.4, 1)
+	game.secondPlayer.SetTint(secondPlayerTint)
+	opponentTints := [][3]float32{{1.4, 0.6, 0.6}, {0.6, 1.4, 0.6}, {1.4, 1.3, 0.5}}
+	for i := range game.opponentPool {
+		game.opponentPool[i] = playerpkg.NewPlayer(playerCar.sprite, shadow.New(playerCar.shadow, shadow.NotSun), gameSettings.SavedSettings.CarSensitivity)
+		var tint ebiten.ColorScale
+		tint.Scale(opponentTints[i][0], opponentTints[i][1], opponentTints[i][2], 1)
+		game.opponentPool[i].SetTint(tint)
+	}
+
+	game.modes = newModes()
+	game.mode = game.modes[0]
+	game.ratingsMode = game.mode
+	if url := gameSettings.SavedSettings.LeaderboardURL; url != "" {
+		game.onlineLeaderboard = leaderboard.NewClient(url, dataDir.Path("leaderboard_queue.json"))
+		go func() {
+			// send what was queued while the game was offline
+			if err := game.onlineLeaderboard.Flush(); err != nil {
+				logger.Error("Failed to send the queued records", "error", err)
+			}
+		}()
 	}
 
 	game.explosionAnimation.SetRepeatable(false)
@@ -203,7 +399,7 @@ func NewGame() (*Game, error) {
 		return nil, fmt.Errorf("failed to set sound: %v", err)
 	}
 
-	game.ApplySettings()
+	game.useProfile(profiles.Current())
 
 	m := color.RGBA{ // headlights
 		R: 255,
@@ -226,14 +422,30 @@ func NewGame() (*Game, error) {
 	game.menuUI = newMenuUI(game, res)
 	game.menuUI.ui, game.menuUI.footerText = game.createUI("Menu", res, game.menuUI.widget, true)
 
-	game.playerRatingsUI = newPlayerRatingsUI(game, res)
-	game.playerRatingsUI.ui, game.playerRatingsUI.footerText = game.createUI("Player ratings", res, game.playerRatingsUI.widget, false)
+	// the records are loaded whenever the ratings are shown
+	game.playerRatingsUI = newPlayerRatingsUI(game, res, nil)
+	game.playerRatingsUI.ui, game.playerRatingsUI.footerText = game.createUI("Player ratings: "+game.ratingsMode.Name(), res, game.playerRatingsUI.widget, false)
+
+	game.modeSelectUI = newModeSelectUI(game, res)
+	game.modeSelectUI.ui, game.modeSelectUI.footerText = game.createUI("Select mode", res, game.modeSelectUI.widget, true)
+
+	game.levelSelectUI = newLevelSelectUI(game, res)
+	game.levelSelectUI.ui, game.levelSelectUI.footerText = game.createUI("Campaign", res, game.levelSelectUI.widget, true)
+
+	game.lobbyUI = newLobbyUI(game, res)
+	game.lobbyUI.ui, game.lobbyUI.footerText = game.createUI("LAN game", res, game.lobbyUI.widget, true)
+
+	game.recoverRecordsUI = newRecoverRecordsUI(game, res)
+	game.recoverRecordsUI.ui, game.recoverRecordsUI.footerText = game.createUI("Records can't be read", res, game.recoverRecordsUI.widget, true)
 
 	game.settingsUI = newSettingsUI(game, res)
 	game.settingsUI.ui, game.settingsUI.footerText = game.createUI("Settings", res, game.settingsUI.widget, false)
 
-	game.setPlayerRatingUI = newSetPlayerRatingUI(game, res)
-	game.setPlayerRatingUI.ui, game.setPlayerRatingUI.footerText = game.createUI("New record!", res, game.setPlayerRatingUI.widget, true)
+	game.profilesUI = newProfilesUI(game, res)
+	game.profilesUI.ui, game.profilesUI.footerText = game.createUI("Profiles", res, game.profilesUI.widget, true)
+
+	game.profileNameUI = newProfileNameUI(res)
+	game.profileNameUI.ui, game.profileNameUI.footerText = game.createUI("Profile", res, game.profileNameUI.widget, true)
 
 	game.changeUIByStage = map[stager.Stage]func(){
 		stager.MainMenuStage: func() {
@@ -243,32 +455,135 @@ func NewGame() (*Game, error) {
 			game.menuUI.footerText.Label = "Song: " + game.audioPlayer.SongName()
 		},
 		stager.StatisticsStage: func() {
-			game.playerRatingsUI = newPlayerRatingsUI(game, res)
-			game.playerRatingsUI.ui, game.playerRatingsUI.footerText = game.createUI("Player ratings", res, game.playerRatingsUI.widget, false)
+			records, ok := game.loadRecords(game.statisticers[game.ratingsMode.ID()], func() {
+				game.stager.SetStage(stager.StatisticsStage)
+			})
+			if !ok {
+				return
+			}
+			game.fetchOnlineTop(game.ratingsMode.ID())
+			game.playerRatingsUI = newPlayerRatingsUI(game, res, statisticer.Top(records, topSize))
+			game.playerRatingsUI.ui, game.playerRatingsUI.footerText = game.createUI("Player ratings: "+game.ratingsMode.Name(), res, game.playerRatingsUI.widget, false)
 
 			game.playerRatingsUI.footerText.Label = "Song: " + game.audioPlayer.SongName()
 		},
-		stager.SetPlayerRecordStage: func() {
-			game.setPlayerRatingUI.text.Label = fmt.Sprintf("Your new record: %d", int(game.player.Points()))
-			game.setPlayerRatingUI.footerText.Label = "Song: " + game.audioPlayer.SongName()
+		stager.DashboardStage: func() {
+			game.dashboard.SetHelp(fmt.Sprintf("%s/%s: mode   %s/%s: profile   %s: ratings   %s: exit",
+				game.controlName(input.MenuLeft), game.controlName(input.MenuRight), game.controlName(input.MenuUp),
+				game.controlName(input.MenuDown), game.controlName(input.SwitchView), game.controlName(input.Back)))
+			var histories []dashboard.History
+			for _, player := range game.profiles.Profiles {
+				for _, mode := range game.modes {
+					board := game.statisticersOf(player)[mode.ID()]
+					if board == nil {
+						continue
+					}
+					records, ok := game.historyOf(player, board)
+					if !ok {
+						return
+					}
+					histories = append(histories, dashboard.History{Mode: mode.Name(), Profile: player.Name, Records: records})
+				}
+			}
+			game.dashboard.SetHistories(histories)
+		},
+		stager.AchievementsStage: func() {
+			game.achievementsUI = newAchievementsUI(game, res)
+			game.achievementsUI.ui, game.achievementsUI.footerText = game.createUI("Achievements", res, game.achievementsUI.widget, false)
+
+			game.achievementsUI.footerText.Label = "Song: " + game.audioPlayer.SongName()
+		},
+		stager.GarageStage: func() {
+			game.garageUI = newGarageUI(game, res)
+			game.garageUI.ui, game.garageUI.footerText = game.createUI("Garage", res, game.garageUI.widget, true)
+
+			game.garageUI.footerText.Label = "Song: " + game.audioPlayer.SongName()
+		},
+		stager.ShopStage: func() {
+			game.shopUI = newShopUI(game, res)
+			game.shopUI.ui, game.shopUI.footerText = game.createUI("Shop", res, game.shopUI.widget, true)
+
+			game.shopUI.footerText.Label = "Song: " + game.audioPlayer.SongName()
+		},
+		stager.ControlsStage: func() {
+			game.controlsUI = newControlsUI(game, res)
+			game.controlsUI.ui, game.controlsUI.footerText = game.createUI("Controls", res, game.controlsUI.widget, false)
+
+			game.controlsUI.footerText.Label = "Song: " + game.audioPlayer.SongName()
+		},
+		stager.ProfilesStage: func() {
+			game.profilesUI = newProfilesUI(game, res)
+			game.profilesUI.ui, game.profilesUI.footerText = game.createUI("Profiles", res, game.profilesUI.widget, true)
+
+			game.profilesUI.footerText.Label = "Song: " + game.audioPlayer.SongName()
+		},
+		stager.ProfileNameStage: func() {
+			game.profileNameUI.text.Label = "Name of the new profile"
+			game.profileNameUI.status.Label = fmt.Sprintf("Press %s to save\nPress %s to go back", game.controlName(input.Confirm), game.controlName(input.Back))
+			if game.renaming != nil {
+				game.profileNameUI.text.Label = "New name of " + game.renaming.Name
+			}
+			game.profileNameUI.footerText.Label = "Song: " + game.audioPlayer.SongName()
+		},
+		stager.ModeSelectStage: func() {
+			game.modeSelectUI.footerText.Label = "Song: " + game.audioPlayer.SongName()
+		},
+		stager.LevelSelectStage: func() {
+			game.levelSelectUI = newLevelSelectUI(game, res)
+			game.levelSelectUI.ui, game.levelSelectUI.footerText = game.createUI("Campaign", res, game.levelSelectUI.widget, true)
+
+			game.levelSelectUI.footerText.Label = "Song: " + game.audioPlayer.SongName()
+		},
+		stager.RecoverRecordsStage: func() {
+			game.recoverRecordsUI.text.Label = fmt.Sprintf("The file %s can't be read.\nYou can go back to the last good copy or start it over.", filepath.Base(game.recovering.Path()))
+			if !game.recovering.HasBackup() {
+				game.recoverRecordsUI.text.Label += "\nThere is no backup to go back to."
+			}
+			game.recoverRecordsUI.footerText.Label = "Song: " + game.audioPlayer.SongName()
+		},
+		stager.LobbyStage: func() {
+			game.lobbyUI.footerText.Label = "Song: " + game.audioPlayer.SongName()
 		},
 		stager.SettingsStage: func() {
 			game.settingsUI.sliderMusicVolume.Current = game.settings.SavedSettings.MusicVolume
 			game.settingsUI.sliderEffectsVolume.Current = game.settings.SavedSettings.EffectsVolume
 			game.settingsUI.sliderCarSensitivity.Current = int(game.settings.SavedSettings.CarSensitivity * 10)
 			game.settingsUI.listResolution.SetSelectedEntry(string(game.settings.SavedSettings.Resolution))
+			game.settingsUI.listPointerSteering.SetSelectedEntry(string(game.settings.SavedSettings.PointerSteering))
+			game.settingsUI.songsHelp.Label = game.songsHelp()
 			game.settingsUI.footerText.Label = "Song: " + game.audioPlayer.SongName()
 		},
 	}
 
 	game.stager.SetOnChange(func(oldStage, newStage stager.Stage) {
 		game.logger.Debug("Setting stage", "stage", newStage)
+		if newStage == stager.GameStage && game.chaserVisible() && !game.player.Dead() {
+			game.siren.Play()
+		} else {
+			game.siren.Pause()
+		}
+		if oldStage == stager.GameStage && newStage == stager.GameOverStage {
+			game.achieve(achievements.Event{Kind: achievements.RunEndEvent})
+			game.payRun()
+		}
+		if newStage == stager.MainMenuStage {
+			game.stopWatching()
+		}
+		if newStage == stager.SettingsStage {
+			game.settingsUI.from = oldStage // the controls page returns to the settings without changing the stage
+		}
 		if buildUI, ok := game.changeUIByStage[newStage]; ok {
 			buildUI()
 		}
 	})
 
 	game.addEvents()
+	game.stager.SetStage(stager.ProfilesStage) // the player picks who is playing first
+	if progressErr != nil {
+		game.recover(unlocks, func() {
+			game.stager.SetStage(stager.ProfilesStage)
+		})
+	}
 
 	return game, nil
 }
@@ -279,12 +594,45 @@ func (game *Game) Update() error {
 		game.mainMenuUI.ui.Update()
 	case stager.MenuStage:
 		game.menuUI.ui.Update()
-	case stager.SetPlayerRecordStage:
-		game.playerRatingsUI.ui.Update()
+	case stager.ProfilesStage:
+		game.profilesUI.ui.Update()
+	case stager.ProfileNameStage:
+		game.profileNameUI.ui.Update()
+	case stager.AchievementsStage:
+		game.achievementsUI.ui.Update()
+	case stager.GarageStage:
+		game.garageUI.ui.Update()
+	case stager.ShopStage:
+		game.shopUI.ui.Update()
+	case stager.ControlsStage:
+		game.controlsUI.ui.Update()
+	case stager.StatisticsStage:
+		if game.updateOnlineTop() {
+			game.changeUIByStage[stager.StatisticsStage]()
+		}
 	case stager.SettingsStage:
 		game.settingsUI.ui.Update()
+	case stager.ModeSelectStage:
+		game.modeSelectUI.ui.Update()
+	case stager.LevelSelectStage:
+		game.levelSelectUI.ui.Update()
+	case stager.EditorStage:
+		game.editor.Update()
+	case stager.LobbyStage:
+		game.lobbyUI.ui.Update()
+		game.updateLobby()
+	case stager.RecoverRecordsStage:
+		game.recoverRecordsUI.ui.Update()
 	}
-	game.eventManager.Update()
+	game.updateGamepads()
+	stage := game.stager.Stage()
+	game.eventManager.Update(func() bool {
+		return game.stager.Stage() != stage // the other actions would run on the next page
+	})
+	if game.stager.Stage() == stage {
+		game.updateClicks()
+	}
+	game.updateToasts()
 	//if time.Since(game.globalTime) < time.Second/time.Duration(60) {
 	//	return nil
 	//}
@@ -297,63 +645,286 @@ func (game *Game) Update() error {
 
 	game.explosionAnimation.Update(0.1)
 
-	if game.player.Dead() {
+	// a player who is out of a LAN race keeps watching it
+	if game.player.Dead() && !game.spectating || game.secondPlayer.Dead() {
 		return nil
 	}
 
-	if game.cars.Collision(game.player.Rectangle) {
-		game.player.SetDead(true)
-		game.logger.Debug("Collision detected")
-		game.explosionAnimation.SetPosition(game.player.X*2.15, game.player.Y*2.15)
-		game.explosionAnimation.Start()
-		game.explosionAnimation.SetCallback(func() {
+	if game.verifying != nil {
+		if !game.steerByReplay() {
 			game.stager.SetStage(stager.GameOverStage)
-			records, err := game.statisticer.Load()
-			if err != nil {
-				log.Fatalf("Failed to load statistics: %v", err)
-			}
-			_, isRecord := preparePlayerRatings(records, game.player.Name(), int(game.player.Points()))
-			if !isRecord {
-				return
-			}
-			game.stager.SetStage(stager.SetPlayerRecordStage)
-		})
-		return nil
+			return nil
+		}
+	} else {
+		game.steerByPointer()
+	}
+	game.steerByInput()
+	if game.step() {
+		game.logger.Debug("Run finished", "mode", game.mode.ID())
+		game.finishRun()
+	}
+	return nil
+}
+
+// step runs one tick of the race with the steering the player has done in it and reports whether the mode
+// has finished the run. A crash ends the run once the explosion is over.
+func (game *Game) step() bool {
+	game.replay.Record(game.tickInput)
+	game.tickInput = 0
+	defer game.trackRun()
+
+	if game.mode.Update(game) {
+		return false // the mode holds the world still in this tick
+	}
+	// a LAN race has run the collisions of every car, the local one included, in the order of the players
+	if !game.player.Dead() && !game.lanRace() && game.collidePlayer() {
+		return false
+	}
+	if game.mode.Finished(game) {
+		game.player.SetDead(true)
+		return true
 	}
 	//game.globalTime = time.Now()
 	game.background.Update(game.scrollSpeed)
-	game.player.Update()
+	game.updateBiome()
+	game.scenery.Update(game.scrollSpeed)
+	game.spawnWaves()
+	game.hazards.Update(game.scrollSpeed, game.cars.EmptyLanes())
+	game.pickups.Update(game.scrollSpeed)
+	game.cars.SetBlockedLanes(game.hazards.BlockedLanes())
+	if !game.player.Dead() {
+		game.player.Update()
+		game.player.AddPoints(game.mode.Points(game))
+		game.clampPlayer(game.player)
+	}
 	game.cars.Update(game.scrollSpeed - 3)
-	return nil
+	if !game.player.Dead() {
+		for _, kind := range game.cars.Passed(game.player.Rectangle) {
+			game.achieve(achievements.Event{Kind: achievements.PassEvent, Vehicle: kind.String()})
+		}
+	}
+	return false
 }
 
-func preparePlayerRatings(records []statisticer.Record, playerName string, playerPoints int) ([]statisticer.Record, bool) {
-	if len(records) == 0 {
-		return append(records, statisticer.NewRecord(playerName, playerPoints)), true
+// collidePlayer runs what the player has run into in this tick and reports whether it was a crash.
+func (game *Game) collidePlayer() bool {
+	if game.cars.Collision(game.player.Rectangle) && !game.player.Absorb() && game.mode.Collision(game, "car") {
+		game.crash(game.player, "car")
+		return true
 	}
+	if hazard, ok := game.hazards.Collision(game.player.Rectangle); ok {
+		switch hazard.Kind() {
+		case hazards.Pothole, hazards.Debris:
+			game.player.Wobble()
+		case hazards.OilSlick:
+			game.player.Slide()
+		default:
+			if !game.player.Absorb() && game.mode.Collision(game, hazard.Kind().String()) {
+				game.crash(game.player, hazard.Kind().String())
+				return true
+			}
+		}
+	}
+	game.countNearMisses()
+	for _, pickup := range game.pickups.Collect(game.player.Rectangle) {
+		if pickup.Kind() == pickups.FuelCan {
+			game.fuelCans++
+			game.player.AddPoints(fuelCanPoints)
+		}
+	}
+	return false
+}
 
-	var isRecord bool
-	for _, record := range records {
-		if playerPoints > record.Points {
-			isRecord = true
-			break
+func (game *Game) countNearMisses() {
+	if nearMisses := game.cars.NearMisses(game.player.Rectangle); nearMisses > 0 {
+		game.nearMisses += nearMisses
+		game.achieve(achievements.Event{Kind: achievements.NearMissEvent, Count: nearMisses})
+	}
+}
+
+// collideOpponent runs what a car other than the player has run into and returns the cause if it was a crash.
+func (game *Game) collideOpponent(player *playerpkg.Player) (string, bool) {
+	if game.cars.Collision(player.Rectangle) {
+		return "car", true
+	}
+	if hazard, ok := game.hazards.Collision(player.Rectangle); ok {
+		switch hazard.Kind() {
+		case hazards.Pothole, hazards.Debris:
+			player.Wobble()
+		case hazards.OilSlick:
+			player.Slide()
+		default:
+			return hazard.Kind().String(), true
+		}
+	}
+	return "", false
+}
+
+func (game *Game) spawnWaves() {
+	batch := game.waves.Due(game.background.Distance())
+	for _, wave := range batch.Waves {
+		for _, placement := range wave.Placements() {
+			game.cars.SpawnScripted(placement.Kind, placement.Lane, placement.Offset)
 		}
 	}
-	if len(records) < 10 {
-		isRecord = true
+	for _, item := range batch.Hazards {
+		if item.HazardKind().Lethal() && !game.hazards.Passable(item.Lane-1) {
+			game.logger.Debug("Skipped a scripted hazard that would close the last lane", "kind", item.Kind, "lane", item.Lane)
+			continue
+		}
+		game.hazards.Spawn(item.HazardKind(), item.Lane-1, scriptedSpawnY)
 	}
-	if !isRecord {
-		return records, false
+	for _, item := range batch.Pickups {
+		game.pickups.Spawn(item.PickupKind(), item.Lane-1, scriptedSpawnY)
 	}
-	records = append(records, statisticer.NewRecord(playerName, playerPoints))
+}
 
-	sort.Slice(records, func(i, j int) bool {
-		return records[i].Points > records[j].Points
-	})
-	if len(records) > 10 {
-		records = records[:10]
+func (game *Game) crash(player *playerpkg.Player, cause string) {
+	game.logger.Debug("Collision detected", "cause", cause)
+	if player == game.player {
+		game.run.cause = cause
+		game.achieve(achievements.Event{Kind: achievements.CollisionEvent, Cause: cause})
+	}
+	game.explode(player)
+	game.explosionAnimation.SetCallback(game.finishRun)
+}
+
+// explode blows the car up without ending the run, e.g. an opponent in a LAN race.
+func (game *Game) explode(player *playerpkg.Player) {
+	player.SetDead(true)
+	game.explosionAnimation.Reset()
+	game.explosionAnimation.SetCallback(nil)
+	game.explosionAnimation.SetPosition(player.X*2.15, player.Y*2.15)
+	game.explosionAnimation.Start()
+}
+
+func (game *Game) finishRun() {
+	game.stager.SetStage(stager.GameOverStage)
+	if game.verifying != nil {
+		return // the run of a replay isn't anyone's
+	}
+	game.saveReplay()
+	if game.leaderboard() == nil {
+		return
+	}
+	records, ok := game.loadRecords(game.leaderboard(), game.finishRun)
+	if !ok {
+		return
+	}
+	records, isRecord := game.addRun(records)
+	if err := game.leaderboard().Save(records); err != nil {
+		game.logger.Error("Failed to save results", "error", err)
+	}
+	game.submitRecord(game.run.record)
+	if isRecord {
+		game.ratingsMode = game.mode
+		game.stager.SetStage(stager.StatisticsStage)
+	}
+}
+
+// loadRecords loads the leaderboard. If the file can't be read it opens the recovery page and reports false,
+// then runs once the file has been repaired.
+func (game *Game) loadRecords(board *statisticer.Statisticer, then func()) ([]statisticer.Record, bool) {
+	records, err := board.Load()
+	if err == nil {
+		return records, true
+	}
+	game.logger.Error("Failed to load statistics", "path", board.Path(), "error", err)
+	game.recover(board, then)
+	return nil, false
+}
+
+// recoverable is a file of the player that can't be read back, the recovery page repairs it.
+type recoverable interface {
+	Path() string
+	HasBackup() bool
+	RestoreBackup() error
+	Reset() error
+}
+
+// recover opens the recovery page for the file, then runs once it has been repaired.
+func (game *Game) recover(file recoverable, then func()) {
+	game.recovering = file
+	game.afterRecovery = then
+	game.stager.SetStage(stager.RecoverRecordsStage)
+}
+
+// leaderboard returns the records of the current mode, it is nil for modes without one.
+func (game *Game) leaderboard() *statisticer.Statisticer {
+	return game.statisticers[game.mode.ID()]
+}
+
+func (game *Game) StartMode(mode Mode) {
+	game.mode = mode
+	game.Reset()
+}
+
+// clampPlayer keeps the car on the road when it is pushed by something other than the steering.
+func (game *Game) clampPlayer(player *playerpkg.Player) {
+	player.X = max(player.X, game.windowWidth/2-480)
+	player.X = min(player.X, game.windowWidth/2+480-player.Width)
+	player.Y = max(player.Y, 0)
+	player.Y = min(player.Y, game.windowHeight-player.Height-10)
+}
+
+// steer moves the car in the direction of the arrow key as long as it stays on the road.
+func (game *Game) steer(player *playerpkg.Player, direction ebiten.Key) {
+	if player.Dead() {
+		return
+	}
+	switch direction {
+	case ebiten.KeyRight:
+		if player.X < game.windowWidth/2+480-player.Width {
+			player.Move(direction)
+		}
+	case ebiten.KeyLeft:
+		if player.X > game.windowWidth/2-480 {
+			player.Move(direction)
+		}
+	case ebiten.KeyUp:
+		if player.Y > 0 {
+			player.Move(direction)
+		}
+	case ebiten.KeyDown:
+		if player.Y < game.windowHeight-player.Height-10 {
+			player.Move(direction)
+		}
+	}
+}
+
+func (game *Game) newRecord() statisticer.Record {
+	record := statisticer.NewRecord(game.player.Name(), int(game.player.Points()), game.biomes.Current().Name)
+	record.Mode = game.mode.ID()
+	record.Seed = game.seed
+	record.Difficulty = game.difficulty()
+	record.Version = Version
+	record.Time = time.Now()
+	record.Duration = game.runDuration()
+	record.Distance = game.background.Distance() - game.startDistance
+	record.MaxSpeed = game.run.maxSpeed
+	record.NearMisses = game.nearMisses
+	record.Cause = game.run.cause
+	record.Replay = game.replay
+	return record
+}
+
+func (game *Game) updateBiome() {
+	if game.biomes.Update(game.background.Distance()) {
+		game.logger.Debug("Biome changed", "biome", game.biomes.Current().Name)
+	}
+	current, next := game.biomes.Current(), game.biomes.Next()
+	game.background.SetImages(current.Road, next.Road, game.biomes.Blend())
+	if game.trafficOverride != nil {
+		game.cars.SetTrafficMix(*game.trafficOverride)
+	} else {
+		game.cars.SetTrafficMix(current.Traffic)
+	}
+	// scenery of the next biome starts arriving from the top of the screen while the road crossfades
+	if game.biomes.Blend() > 0 {
+		game.scenery.SetKinds(next.Scenery)
+	} else {
+		game.scenery.SetKinds(current.Scenery)
 	}
-	return records, true
 }
 
 func (game *Game) Draw(screen *ebiten.Image) {
@@ -364,12 +935,34 @@ func (game *Game) Draw(screen *ebiten.Image) {
 		game.menuUI.ui.Draw(screen)
 	case stager.StatisticsStage:
 		game.playerRatingsUI.ui.Draw(screen)
-	case stager.SetPlayerRecordStage:
-		game.setPlayerRatingUI.ui.Draw(screen)
+	case stager.ProfilesStage:
+		game.profilesUI.ui.Draw(screen)
+	case stager.ProfileNameStage:
+		game.profileNameUI.ui.Draw(screen)
+	case stager.AchievementsStage:
+		game.achievementsUI.ui.Draw(screen)
+	case stager.GarageStage:
+		game.garageUI.ui.Draw(screen)
+	case stager.ShopStage:
+		game.shopUI.ui.Draw(screen)
+	case stager.ControlsStage:
+		game.controlsUI.ui.Draw(screen)
 	case stager.GameStage, stager.GameOverStage:
 		game.drawGameStage(screen)
 	case stager.SettingsStage:
 		game.settingsUI.ui.Draw(screen)
+	case stager.ModeSelectStage:
+		game.modeSelectUI.ui.Draw(screen)
+	case stager.LevelSelectStage:
+		game.levelSelectUI.ui.Draw(screen)
+	case stager.EditorStage:
+		game.editor.Draw(screen)
+	case stager.DashboardStage:
+		game.dashboard.Draw(screen)
+	case stager.LobbyStage:
+		game.lobbyUI.ui.Draw(screen)
+	case stager.RecoverRecordsStage:
+		game.recoverRecordsUI.ui.Draw(screen)
 	default:
 	}
 }
@@ -378,119 +971,218 @@ func (game *Game) Layout(screenWidthPx, screenHeightPx int) (int, int) {
 	return screenWidthPx, screenHeightPx
 }
 
+// restart starts the next run after the game is over, a LAN race goes back to the lobby to start the next one.
+func (game *Game) restart() {
+	if game.lanRace() {
+		game.leaveRace()
+		return
+	}
+	game.Reset()
+}
+
+// pause opens the menu, leaves the LAN race or returns to the editor from a test play.
+func (game *Game) pause() {
+	if mode, ok := game.mode.(*campaignMode); ok && mode.testPlay {
+		game.stager.SetStage(stager.EditorStage)
+		return
+	}
+	if game.lanRace() {
+		game.leaveRace()
+		return
+	}
+	game.stager.SetStage(stager.MenuStage)
+}
+
 func (game *Game) addEvents() {
-	game.eventManager.AddPressEvent(ebiten.KeyRight, func() {
+	game.eventManager.AddPressEvent(input.SteerRight, func() {
 		switch game.stager.Stage() {
 		case stager.GameStage:
-			if !game.player.Dead() && game.player.X < game.windowWidth/2+370 {
-				game.player.Move(ebiten.KeyRight)
+			if !game.modeSteering {
+				game.steerPlayer(ebiten.KeyRight)
 			}
 		}
 	})
-	game.eventManager.AddPressedEvent(ebiten.KeyRight, func() {
+	game.eventManager.AddPressedEvent(input.MenuRight, func() {
 		switch game.stager.Stage() {
 		case stager.SettingsStage:
 			game.settingsUI.buttons.Next()
+		case stager.StatisticsStage:
+			game.switchRatingsMode(1)
+		case stager.DashboardStage:
+			game.dashboard.SwitchMode(1)
 		}
 	})
-	game.eventManager.AddPressEvent(ebiten.KeyLeft, func() {
+	game.eventManager.AddPressEvent(input.SteerLeft, func() {
 		switch game.stager.Stage() {
 		case stager.GameStage:
-			if !game.player.Dead() && game.player.X > game.windowWidth/2-480 {
-				game.player.Move(ebiten.KeyLeft)
+			if !game.modeSteering {
+				game.steerPlayer(ebiten.KeyLeft)
 			}
 		}
 	})
-	game.eventManager.AddPressedEvent(ebiten.KeyLeft, func() {
+	game.eventManager.AddPressedEvent(input.MenuLeft, func() {
 		switch game.stager.Stage() {
 		case stager.SettingsStage:
 			game.settingsUI.buttons.Before()
+		case stager.StatisticsStage:
+			game.switchRatingsMode(-1)
+		case stager.DashboardStage:
+			game.dashboard.SwitchMode(-1)
 		}
 	})
-	game.eventManager.AddPressEvent(ebiten.KeyUp, func() {
+	game.eventManager.AddPressEvent(input.Throttle, func() {
 		switch game.stager.Stage() {
 		case stager.GameStage:
-			if !game.player.Dead() && game.player.Y > 0 {
-				game.player.Move(ebiten.KeyUp)
+			if !game.modeSteering {
+				game.steerPlayer(ebiten.KeyUp)
 			}
 		case stager.GameOverStage:
 		}
 	})
-	game.eventManager.AddPressedEvent(ebiten.KeyUp, func() {
+	game.eventManager.AddPressedEvent(input.MenuUp, func() {
 		switch game.stager.Stage() {
 		case stager.MainMenuStage:
 			game.mainMenuUI.buttons.Before()
 		case stager.MenuStage:
 			game.menuUI.buttons.Before()
+		case stager.ModeSelectStage:
+			game.modeSelectUI.buttons.Before()
+		case stager.LevelSelectStage:
+			game.levelSelectUI.buttons.Before()
+		case stager.LobbyStage:
+			game.lobbyUI.buttons.Before()
+		case stager.RecoverRecordsStage:
+			game.recoverRecordsUI.buttons.Before()
+		case stager.ProfilesStage:
+			game.profilesUI.buttons.Before()
+		case stager.GarageStage:
+			game.garageUI.buttons.Before()
+		case stager.ShopStage:
+			game.shopUI.buttons.Before()
+		case stager.ControlsStage:
+			game.controlsUI.buttons.Before()
+		case stager.DashboardStage:
+			game.dashboard.SwitchProfile(-1)
 		}
 	})
-	game.eventManager.AddPressEvent(ebiten.KeyDown, func() {
+	game.eventManager.AddPressEvent(input.Brake, func() {
 		switch game.stager.Stage() {
 		case stager.GameStage:
-			if !game.player.Dead() && game.player.Y < game.windowHeight-210 {
-				game.player.Move(ebiten.KeyDown)
+			if !game.modeSteering {
+				game.steerPlayer(ebiten.KeyDown)
 			}
 		case stager.GameOverStage:
 		}
 	})
-	game.eventManager.AddPressedEvent(ebiten.KeyDown, func() {
+	game.eventManager.AddPressedEvent(input.MenuDown, func() {
 		switch game.stager.Stage() {
 		case stager.MainMenuStage:
 			game.mainMenuUI.buttons.Next()
 		case stager.MenuStage:
 			game.menuUI.buttons.Next()
+		case stager.ModeSelectStage:
+			game.modeSelectUI.buttons.Next()
+		case stager.LevelSelectStage:
+			game.levelSelectUI.buttons.Next()
+		case stager.LobbyStage:
+			game.lobbyUI.buttons.Next()
+		case stager.RecoverRecordsStage:
+			game.recoverRecordsUI.buttons.Next()
+		case stager.ProfilesStage:
+			game.profilesUI.buttons.Next()
+		case stager.GarageStage:
+			game.garageUI.buttons.Next()
+		case stager.ShopStage:
+			game.shopUI.buttons.Next()
+		case stager.ControlsStage:
+			game.controlsUI.buttons.Next()
+		case stager.DashboardStage:
+			game.dashboard.SwitchProfile(1)
 		}
 	})
-	game.eventManager.AddPressedEvent(ebiten.KeyEscape, func() {
+	game.eventManager.AddPressedEvent(input.Pause, func() {
 		switch game.stager.Stage() {
-		case stager.GameStage, stager.GameOverStage:
-			game.stager.SetStage(stager.MenuStage)
+		case stager.GameStage:
+			game.pause()
+		case stager.MenuStage:
+			game.stager.SetStage(stager.GameStage)
+		}
+	})
+	game.eventManager.AddPressedEvent(input.Back, func() {
+		switch game.stager.Stage() {
+		case stager.GameOverStage:
+			game.pause()
 		case stager.MenuStage:
 			game.stager.SetStage(stager.GameStage)
 		case stager.SettingsStage:
-			game.stager.RecoveryLastStage()
-		case stager.StatisticsStage:
+			game.leaveSettings()
+		case stager.ControlsStage:
+			game.leaveControls()
+		case stager.StatisticsStage, stager.ModeSelectStage, stager.LevelSelectStage, stager.EditorStage, stager.RecoverRecordsStage,
+			stager.DashboardStage, stager.ProfilesStage, stager.AchievementsStage, stager.GarageStage,
+			stager.ShopStage:
+			game.stager.SetStage(stager.MainMenuStage)
+		case stager.ProfileNameStage:
+			game.profileNameUI.textInput.SetText("")
+			game.stager.SetStage(stager.ProfilesStage)
+		case stager.LobbyStage:
+			game.closeSession()
 			game.stager.SetStage(stager.MainMenuStage)
 		}
 	})
-	game.eventManager.AddPressedEvent(ebiten.KeyEnter, func() {
+	game.eventManager.AddPressedEvent(input.Confirm, func() {
 		switch game.stager.Stage() {
 		case stager.GameStage:
 		case stager.GameOverStage:
-			game.Reset()
+			game.restart()
 		case stager.MainMenuStage:
 			game.mainMenuUI.buttons.Pressed()
 		case stager.MenuStage:
 			game.menuUI.buttons.Pressed()
 		case stager.SettingsStage:
 			game.settingsUI.buttons.Pressed()
-		case stager.StatisticsStage:
+		case stager.ModeSelectStage:
+			game.modeSelectUI.buttons.Pressed()
+		case stager.LevelSelectStage:
+			game.levelSelectUI.buttons.Pressed()
+		case stager.LobbyStage:
+			game.lobbyUI.buttons.Pressed()
+		case stager.RecoverRecordsStage:
+			game.recoverRecordsUI.buttons.Pressed()
+		case stager.StatisticsStage, stager.DashboardStage, stager.AchievementsStage:
 			game.stager.SetStage(stager.MainMenuStage)
-		case stager.SetPlayerRecordStage:
-			if game.setPlayerRatingUI.textInput.GetText() == "" {
+		case stager.ProfilesStage:
+			game.profilesUI.buttons.Pressed()
+		case stager.GarageStage:
+			game.garageUI.buttons.Pressed()
+		case stager.ShopStage:
+			game.shopUI.buttons.Pressed()
+		case stager.ControlsStage:
+			game.controlsUI.buttons.Pressed()
+		case stager.ProfileNameStage:
+			if err := game.nameProfile(game.profileNameUI.textInput.GetText()); err != nil {
+				game.profileNameUI.status.Label = err.Error()
 				break
 			}
-			game.player.SetName(game.setPlayerRatingUI.textInput.GetText())
-			game.setPlayerRatingUI.textInput.SetText("")
-
-			records, err := game.statisticer.Load()
-			if err != nil {
-				log.Fatalf("Failed to load statistics: %v", err)
-			}
-			resultRecords, _ := preparePlayerRatings(records, game.player.Name(), int(game.player.Points()))
-			if err := game.statisticer.Save(resultRecords); err != nil {
-				log.Fatalf("Failed to save results: %v", err)
-			}
+			game.profileNameUI.textInput.SetText("")
+		}
+	})
+	game.eventManager.AddPressedEvent(input.SwitchView, func() {
+		switch game.stager.Stage() {
+		case stager.StatisticsStage:
+			game.stager.SetStage(stager.DashboardStage)
+			game.dashboard.Show(game.ratingsMode.Name(), game.profile.Name)
+		case stager.DashboardStage:
 			game.stager.SetStage(stager.StatisticsStage)
 		}
 	})
-	game.eventManager.AddPressedEvent(ebiten.KeyZ, func() {
+	game.eventManager.AddPressedEvent(input.PreviousTrack, func() {
 		game.audioPlayer.Before()
 		if buildUI, ok := game.changeUIByStage[game.stager.Stage()]; ok {
 			buildUI()
 		}
 	})
-	game.eventManager.AddPressedEvent(ebiten.KeyX, func() {
+	game.eventManager.AddPressedEvent(input.NextTrack, func() {
 		game.audioPlayer.Next()
 		if buildUI, ok := game.changeUIByStage[game.stager.Stage()]; ok {
 			buildUI()
@@ -498,11 +1190,24 @@ func (game *Game) addEvents() {
 	})
 }
 
+// switchRatingsMode moves to the next mode that has a leaderboard.
+func (game *Game) switchRatingsMode(step int) {
+	index := slices.Index(game.modes, game.ratingsMode)
+	for range game.modes {
+		index = (index + step + len(game.modes)) % len(game.modes)
+		if game.statisticers[game.modes[index].ID()] != nil {
+			break
+		}
+	}
+	game.ratingsMode = game.modes[index]
+	game.changeUIByStage[stager.StatisticsStage]()
+}
+
 func (game *Game) calculateObjects() {
 	game.objects = []raycasting.Object{
 		ConvertRectangleToObject(*rectangle.New(0, 0, game.windowWidth, game.windowHeight)),
 		*raycasting.NewObject([]raycasting.Line{{ // right ray
-			float64(game.player.X) + 100,
+			float64(game.player.X) + game.player.Width - 10,
 			float64(game.player.Y) + 2,
 			game.player.X - game.startPlayerX + game.windowWidth - 500,
 			game.player.Y - game.windowHeight}}),
@@ -549,16 +1254,63 @@ func ConvertRectangleToObject(rectangle rectangle.Rectangle) raycasting.Object {
 func (game *Game) Reset() {
 	sunDirection := shadow.DirectionShadow(1)
 	game.sunDirection = sunDirection
+	game.seed = rand.Uint64()
+	if game.fixedSeed != nil {
+		game.seed = *game.fixedSeed
+	}
+	if game.verifying != nil {
+		game.seed = game.verifying.Seed
+		game.watchTick = 0
+	}
 
 	game.stager.SetStage(stager.GameStage)
 	game.player.Reset()
+	game.drive(game.player, game.chosenVehicle(), game.chosenUpgrades())
+	game.player.SetTint(paintTint(game.profile.Paint))
 	game.player.SetPosition(game.windowWidth/2-float64(game.player.Rectangle.Width)/2, game.windowHeight/2)
 	game.player.SetSunDirection(sunDirection)
 	game.startPlayerX = game.player.X
 	game.startPlayerY = game.player.Y
-
-	game.cars.Reset()
+	game.secondPlayer.Reset()
+	game.secondPlayer.SetSunDirection(sunDirection)
+	game.player.SetSpeed(game.settings.RawSettings.CarSensitivity) // a LAN race drives at the speed of the host
+	if game.verifying != nil {
+		game.player.SetSpeed(game.verifying.Speed)
+	}
+	game.nearMisses = 0
+	game.fuelCans = 0
+
+	// modes configure the world before it is reset
+	game.siren.Pause()
+	game.biomes.Fix("")
+	game.trafficOverride = nil
+	game.trafficScript = nil
+	game.startDistance = 0
+	game.twoPlayers = false
+	game.modeSteering = false
+	game.spectating = false
+	game.opponents = game.opponents[:0]
+	game.hazards.SetEnabled(true)
+	game.pickups.SetEnabled(false)
+	game.mode.Setup(game)
+	game.startReplay()
+	game.startRunStats()
+	game.achieve(achievements.Event{Kind: achievements.RunStartEvent})
+
+	game.background.Reset()
+	game.background.SetDistance(game.startDistance)
+	game.biomes.Reset(game.seed)
+	game.updateBiome()
+
+	game.hazards.Reset(game.seed)
+	game.cars.SetBlockedLanes(game.hazards.BlockedLanes())
+	game.cars.Reset(game.seed)
 	game.cars.SetSunDirection(sunDirection)
+	game.waves = traffic.NewRunner(game.trafficScript)
+	game.waves.Skip(game.startDistance)
+	game.scenery.Reset(game.seed)
+	game.scenery.SetSunDirection(sunDirection)
+	game.pickups.Reset(game.seed)
 	game.explosionAnimation.Reset()
 }
 
@@ -600,6 +1352,7 @@ func (game *Game) ApplySettings() {
 
 	game.audioPlayer.SetVolume(float64(game.settings.SavedSettings.MusicVolume) / 100)
 	game.explosionAnimation.SetVolume(float64(game.settings.SavedSettings.EffectsVolume) / 100)
+	game.siren.SetVolume(float64(game.settings.SavedSettings.EffectsVolume) / 100)
 }
 
 func (game *Game) Close() {
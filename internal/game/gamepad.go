@@ -0,0 +1,22 @@
+package game
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/VxVxN/game/internal/stager"
+)
+
+// updateGamepads handles the gamepads plugged in and out. A run is paused when a gamepad is unplugged,
+// so the player doesn't crash while plugging it back in.
+func (game *Game) updateGamepads() {
+	connected, disconnected := game.gamepads.Update()
+	for _, id := range connected {
+		game.logger.Info("Gamepad connected", "name", ebiten.GamepadName(id), "standard", ebiten.IsStandardGamepadLayoutAvailable(id))
+	}
+	for _, id := range disconnected {
+		game.logger.Info("Gamepad disconnected", "id", id)
+	}
+	if len(disconnected) > 0 && game.stager.Stage() == stager.GameStage && !game.lanRace() {
+		game.stager.SetStage(stager.MenuStage)
+	}
+}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"fmt"
+	"maps"
+	"path"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+
+	"github.com/VxVxN/game/internal/garage"
+	"github.com/VxVxN/game/internal/shadow"
+	"github.com/VxVxN/game/internal/shop"
+	playerpkg "github.com/VxVxN/game/pkg/player"
+)
+
+// vehicleImages are the images of a vehicle cut from the sheets in the assets.
+type vehicleImages struct {
+	sprite *ebiten.Image
+	shadow *ebiten.Image
+}
+
+// loadVehicleImages cuts the sprite and the shadow of every vehicle from its sheet, a sheet is read once.
+func loadVehicleImages(assetPath string, vehicles []*garage.Vehicle) (map[string]vehicleImages, error) {
+	sheets := make(map[string]*ebiten.Image)
+	cut := func(sprite garage.Sprite) (*ebiten.Image, error) {
+		sheet, ok := sheets[sprite.Sheet]
+		if !ok {
+			var err error
+			sheet, _, err = ebitenutil.NewImageFromFile(path.Join(assetPath, sprite.Sheet))
+			if err != nil {
+				return nil, fmt.Errorf("failed to init %s image: %v", sprite.Sheet, err)
+			}
+			sheets[sprite.Sheet] = sheet
+		}
+		return sheet.SubImage(sprite.Bounds()).(*ebiten.Image), nil
+	}
+
+	images := make(map[string]vehicleImages, len(vehicles))
+	for _, vehicle := range vehicles {
+		sprite, err := cut(vehicle.Sprite)
+		if err != nil {
+			return nil, err
+		}
+		vehicleShadow, err := cut(vehicle.Shadow)
+		if err != nil {
+			return nil, err
+		}
+		images[vehicle.ID] = vehicleImages{sprite: sprite, shadow: vehicleShadow}
+	}
+	return images, nil
+}
+
+// chosenVehicle returns the vehicle of the profile, or the one of the replay that is verified.
+func (game *Game) chosenVehicle() *garage.Vehicle {
+	if game.verifying != nil {
+		if vehicle := garage.Find(game.vehicles, game.verifying.Vehicle); vehicle != nil {
+			return vehicle
+		}
+	} else if vehicle := garage.Find(game.vehicles, game.profile.Vehicle); vehicle != nil && game.owns(vehicle) {
+		return vehicle
+	}
+	return garage.Find(game.vehicles, garage.DefaultID)
+}
+
+// chosenUpgrades returns the upgrades of the profile, or the ones of the replay that is verified.
+func (game *Game) chosenUpgrades() map[string]int {
+	if game.verifying != nil {
+		return game.verifying.Upgrades
+	}
+	return maps.Clone(game.profile.Purchases.Upgrades)
+}
+
+func (game *Game) owns(vehicle *garage.Vehicle) bool {
+	return game.profile.Purchases.OwnsVehicle(vehicle.ID, vehicle.Price)
+}
+
+// drive puts the player in the vehicle with the upgrades.
+func (game *Game) drive(player *playerpkg.Player, vehicle *garage.Vehicle, upgrades map[string]int) {
+	images := game.vehicleImages[vehicle.ID]
+	player.SetVehicle(images.sprite, shadow.New(images.shadow, shadow.NotSun), vehicle.HitboxBounds(), shop.Apply(vehicle.Handling, upgrades))
+	if player == game.player {
+		game.vehicle = vehicle
+		game.upgrades = upgrades
+	}
+}
+
+// chooseVehicle keeps the vehicle for the profile, it is driven from the next run on.
+func (game *Game) chooseVehicle(vehicle *garage.Vehicle) {
+	game.profile.Vehicle = vehicle.ID
+	game.saveProfiles()
+}
@@ -0,0 +1,135 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/VxVxN/game/internal/achievements"
+	"github.com/VxVxN/game/internal/datadir"
+	"github.com/VxVxN/game/internal/profile"
+	"github.com/VxVxN/game/pkg/background"
+	"github.com/VxVxN/game/pkg/statisticer"
+)
+
+// topSize is how many records the ratings show, a run that gets into them opens the ratings.
+const topSize = 10
+
+// runStats is what the history keeps of a run besides the points.
+type runStats struct {
+	ticks    int
+	maxSpeed float64 // km/h
+	lastY    float64
+	cause    string
+	record   statisticer.Record // the finished run as it was added to the history
+	coins    int                // paid out when the run is over
+}
+
+func (game *Game) startRunStats() {
+	game.run = runStats{lastY: game.player.Y}
+}
+
+// trackRun measures the speed of the car over the road, the road scrolls and the car moves on the screen.
+func (game *Game) trackRun() {
+	game.run.ticks++
+	if game.player.Dead() {
+		return
+	}
+	game.achieve(achievements.Event{Kind: achievements.TickEvent, Points: int(game.player.Points())})
+	pixels := game.scrollSpeed + game.run.lastY - game.player.Y
+	game.run.maxSpeed = max(game.run.maxSpeed, pixels*ebiten.DefaultTPS/background.PixelsPerMetre*3.6)
+	game.run.lastY = game.player.Y
+}
+
+// addRun puts the finished run at the end of the history and reports whether it got into the top.
+func (game *Game) addRun(records []statisticer.Record) ([]statisticer.Record, bool) {
+	game.run.record = game.newRecord()
+	records = append(records, game.run.record)
+	for _, record := range statisticer.Top(records, topSize) {
+		if record.Time.Equal(game.run.record.Time) {
+			return records, true
+		}
+	}
+	return records, false
+}
+
+func (game *Game) runDuration() time.Duration {
+	return time.Duration(game.run.ticks) * time.Second / ebiten.DefaultTPS
+}
+
+// PrintStats writes the leaderboards and the history of every profile to w, or only the ones of the profile
+// and the mode if they are given. Nothing is written to the data directory, corrupt files are repaired
+// by the game.
+func PrintStats(w io.Writer, dataDir datadir.Dir, profileName, modeID string) error {
+	profiles, err := profile.Read(dataDir.Path("profiles.json"), dataDir.Path("profiles"))
+	if errors.Is(err, profile.ErrCorrupt) {
+		fmt.Fprintf(w, "The profiles file is corrupt, the profiles are read from the backup or their directories: %v\n", err)
+	} else if err != nil {
+		return fmt.Errorf("failed to load profiles: %v", err)
+	}
+	players := profiles.Profiles
+	if profileName != "" {
+		player := profiles.Find(profileName)
+		if player == nil {
+			return fmt.Errorf("there is no profile %q", profileName)
+		}
+		players = []*profile.Profile{player}
+	}
+	if len(players) == 0 {
+		fmt.Fprintln(w, "No profiles yet")
+		return nil
+	}
+	modes := newModes()
+	if modeID != "" {
+		mode, err := findMode(modes, modeID)
+		if err != nil {
+			return err
+		}
+		modes = []Mode{mode}
+	}
+
+	for _, player := range players {
+		fmt.Fprintln(w, player.Name)
+		statisticers := statisticersIn(profiles.Dir(player), modes)
+		for _, mode := range modes {
+			board := statisticers[mode.ID()]
+			if board == nil {
+				continue
+			}
+			fmt.Fprintf(w, "  %s\n", mode.Name())
+			records, err := board.Read()
+			if err != nil {
+				fmt.Fprintf(w, "    The records can't be read: %v\n", err)
+				continue
+			}
+			if len(records) == 0 {
+				fmt.Fprintln(w, "    No runs yet")
+				continue
+			}
+			average := statisticer.Average(records)
+			current, longest := statisticer.DayStreaks(records, time.Now())
+			fmt.Fprintf(w, "    Runs: %d   Average: %.0f points, %.0f m   Days in a row: %d (longest %d)\n",
+				average.Runs, average.Points, average.Distance, current, longest)
+			for i, record := range statisticer.Top(records, topSize) {
+				fmt.Fprintf(w, "    %2d. %6d  %-18s %s\n", i+1, record.Points, driven(record), record.Biome)
+			}
+			fmt.Fprintln(w, "    History")
+			for _, record := range records {
+				fmt.Fprintf(w, "      %-18s %6d points %7.0f m %8s  %-12s %s\n", driven(record), record.Points,
+					record.Distance, record.Duration.Round(time.Second), record.Biome, record.Cause)
+			}
+		}
+	}
+	return nil
+}
+
+// driven returns when the run of the record was driven.
+func driven(record statisticer.Record) string {
+	if record.Time.IsZero() {
+		return "before the history"
+	}
+	return record.Time.Local().Format("2006-01-02 15:04")
+}
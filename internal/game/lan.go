@@ -0,0 +1,321 @@
+package game
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+
+	"github.com/VxVxN/game/internal/garage"
+	"github.com/VxVxN/game/internal/netplay"
+	"github.com/VxVxN/game/internal/stager"
+	playerpkg "github.com/VxVxN/game/pkg/player"
+)
+
+const (
+	lanAddress       = "127.0.0.1:7777"
+	dropLaggingAfter = 5 * time.Second
+)
+
+// lanMode races every player of a LAN session on the same seed. All sides simulate all cars
+// from the shared input, so the local car is handled like the others in the order of the players.
+type lanMode struct {
+	session *netplay.Session
+	seed    uint64
+	speed   float64
+	tick    int
+	names   []string
+	cars    []*playerpkg.Player
+	out     []bool
+	waiting bool
+	result  string
+}
+
+func (mode *lanMode) ID() string {
+	return "lan"
+}
+
+func (mode *lanMode) Name() string {
+	return "LAN race"
+}
+
+func (mode *lanMode) Setup(game *Game) {
+	mode.tick = 0
+	mode.waiting = false
+	mode.result = ""
+	game.seed = mode.seed
+	game.modeSteering = true
+
+	mode.names = mode.session.Players()
+	mode.cars = make([]*playerpkg.Player, len(mode.names))
+	mode.out = make([]bool, len(mode.names))
+	local := mode.session.LocalPlayer()
+	game.drive(game.player, garage.Find(game.vehicles, garage.DefaultID), nil) // everyone races the same car
+	game.opponents = game.opponents[:0]
+	for i := range mode.cars {
+		car := game.player
+		if i != local {
+			car = game.opponentPool[len(game.opponents)]
+			car.Reset()
+			car.SetSunDirection(game.sunDirection)
+			game.opponents = append(game.opponents, car)
+		}
+		car.SetSpeed(mode.speed)
+		car.SetPosition(game.windowWidth/2-450+float64(i)*800/float64(max(len(mode.cars)-1, 1)), game.windowHeight/2)
+		mode.cars[i] = car
+	}
+}
+
+// Update waits until the input of every player for the tick has arrived, then steers all cars and runs
+// what they have run into.
+func (mode *lanMode) Update(game *Game) bool {
+	if mode.session.Err() != nil {
+		return false // Finished ends the race
+	}
+	inputs, ok := mode.session.Inputs(mode.tick)
+	if !ok {
+		mode.waiting = true
+		if mode.session.IsHost() && mode.session.Stalled() > dropLaggingAfter {
+			mode.session.DropLagging(mode.tick)
+		}
+		return true
+	}
+	mode.waiting = false
+	mode.session.SendInput(mode.tick, localInput(game))
+	for i := range mode.cars {
+		if !mode.out[i] && mode.session.Left(i, mode.tick) {
+			mode.knockOut(game, i)
+		}
+	}
+	mode.tick++
+
+	for i, car := range mode.cars {
+		if mode.out[i] {
+			continue
+		}
+		for _, direction := range directions(inputs[i]) {
+			game.steer(car, direction)
+		}
+	}
+	for i := range mode.cars {
+		for j := i + 1; j < len(mode.cars); j++ {
+			if !mode.out[i] && !mode.out[j] {
+				bump(mode.cars[i], mode.cars[j])
+			}
+		}
+	}
+	for i, car := range mode.cars {
+		if mode.out[i] {
+			continue
+		}
+		if _, crashed := game.collideOpponent(car); crashed {
+			mode.knockOut(game, i)
+		} else if car == game.player {
+			game.countNearMisses()
+		}
+	}
+	for i, car := range mode.cars {
+		if mode.out[i] || car == game.player {
+			continue // the local car is updated with the world like in every other mode
+		}
+		car.Update()
+		car.AddPoints(pointsPerTick)
+		game.clampPlayer(car)
+	}
+	return false
+}
+
+func (mode *lanMode) knockOut(game *Game, player int) {
+	mode.out[player] = true
+	game.explode(mode.cars[player])
+	if mode.cars[player] == game.player {
+		game.spectating = true
+	}
+}
+
+// Collision has nothing to do, Update has already run the crashes of every car in the order of the players.
+func (mode *lanMode) Collision(game *Game, cause string) bool {
+	return false
+}
+
+func (mode *lanMode) Points(game *Game) float64 {
+	return pointsPerTick
+}
+
+// Finished ends the race once a single car is left, the last survivor wins.
+func (mode *lanMode) Finished(game *Game) bool {
+	if err := mode.session.Err(); err != nil {
+		mode.result = err.Error()
+		return true
+	}
+	winner, alive := -1, 0
+	for i, out := range mode.out {
+		if !out {
+			winner = i
+			alive++
+		}
+	}
+	if alive > 1 || alive == 1 && len(mode.cars) == 1 {
+		return false
+	}
+	if winner >= 0 {
+		mode.result = mode.names[winner] + " wins!"
+	} else {
+		mode.result = "Everybody has crashed, it's a draw"
+	}
+	game.spectating = false
+	return true
+}
+
+func (mode *lanMode) DrawHUD(game *Game, screen *ebiten.Image, textFace text.Face) {
+	y := 20.0
+	for i, name := range mode.names {
+		status := fmt.Sprintf("%d", int(mode.cars[i].Points()))
+		if mode.out[i] {
+			status += " (out)"
+		}
+		drawHUDText(screen, fmt.Sprintf("%s: %s", name, status), textFace, 20, y)
+		y += 30
+		if mode.cars[i] != game.player && !mode.out[i] {
+			drawHUDText(screen, name, textFace, mode.cars[i].X, mode.cars[i].Y-30)
+		}
+	}
+	if mode.waiting {
+		drawHUDText(screen, "Waiting for the other players...", textFace, 20, y+10)
+	}
+	if mode.result != "" {
+		drawHUDText(screen, mode.result, textFace, 20, y+40)
+	}
+}
+
+func localInput(game *Game) netplay.Input {
+	var input netplay.Input
+	if game.player.Dead() {
+		return input
+	}
+	for _, direction := range game.heldDirections(playerOneControls, game.player) {
+		switch direction {
+		case ebiten.KeyLeft:
+			input |= netplay.InputLeft
+		case ebiten.KeyRight:
+			input |= netplay.InputRight
+		case ebiten.KeyUp:
+			input |= netplay.InputUp
+		case ebiten.KeyDown:
+			input |= netplay.InputDown
+		}
+	}
+	return input
+}
+
+func directions(input netplay.Input) []ebiten.Key {
+	var keys []ebiten.Key
+	if input&netplay.InputLeft != 0 {
+		keys = append(keys, ebiten.KeyLeft)
+	}
+	if input&netplay.InputRight != 0 {
+		keys = append(keys, ebiten.KeyRight)
+	}
+	if input&netplay.InputUp != 0 {
+		keys = append(keys, ebiten.KeyUp)
+	}
+	if input&netplay.InputDown != 0 {
+		keys = append(keys, ebiten.KeyDown)
+	}
+	return keys
+}
+
+func (game *Game) lanName() string {
+	if game.player.Name() != "" {
+		return game.player.Name()
+	}
+	return "Player"
+}
+
+// lanScreen is the size of the game, the simulation depends on it so every player of a race must share it.
+func (game *Game) lanScreen() string {
+	return fmt.Sprintf("%dx%d", int(game.windowWidth), int(game.windowHeight))
+}
+
+func (game *Game) hostRace(address string) error {
+	if game.session != nil {
+		return nil
+	}
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("failed to parse address: %v", err)
+	}
+	game.session, err = netplay.Host(":"+port, game.lanName(), game.lanScreen())
+	return err
+}
+
+func (game *Game) joinRace(address string) error {
+	if game.session != nil {
+		return nil
+	}
+	session, err := netplay.Join(address, game.lanName(), game.lanScreen())
+	if err != nil {
+		return err
+	}
+	game.session = session
+	return nil
+}
+
+func (game *Game) startRace() {
+	if game.session.IsHost() {
+		if err := game.session.Start(rand.Uint64(), game.settings.RawSettings.CarSensitivity); err != nil {
+			game.logger.Error("Failed to start the race", "error", err)
+			return
+		}
+	}
+	seed, speed, _ := game.session.Started()
+	game.StartMode(&lanMode{session: game.session, seed: seed, speed: speed})
+}
+
+// leaveRace closes the connection and goes back to the lobby.
+func (game *Game) leaveRace() {
+	game.closeSession()
+	game.mode = game.modes[0]
+	game.stager.SetStage(stager.LobbyStage)
+}
+
+func (game *Game) closeSession() {
+	if game.session == nil {
+		return
+	}
+	game.session.Close()
+	game.session = nil
+}
+
+func (game *Game) lanRace() bool {
+	_, ok := game.mode.(*lanMode)
+	return ok
+}
+
+// updateLobby shows who is in the lobby and starts the race on the clients once the host has started it.
+func (game *Game) updateLobby() {
+	if game.session == nil {
+		game.lobbyUI.status.Label = "Host a race or join one by the address of the host\n" + game.lobbyUI.message
+		return
+	}
+	if err := game.session.Err(); err != nil {
+		game.lobbyUI.message = err.Error()
+		game.closeSession()
+		return
+	}
+	if _, _, started := game.session.Started(); started && !game.session.IsHost() {
+		game.startRace()
+		return
+	}
+	status := "Waiting for the host to start\n"
+	if game.session.IsHost() {
+		status = fmt.Sprintf("Hosting on port %d, press Start when everybody is in\n", game.session.Addr().(*net.TCPAddr).Port)
+	}
+	for i, name := range game.session.Players() {
+		status += fmt.Sprintf("\n%d. %s", i+1, name)
+	}
+	game.lobbyUI.status.Label = status
+}
@@ -0,0 +1,142 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// Mode holds the rules of a run: how it starts, what every tick does, how points are earned and when it ends.
+type Mode interface {
+	// ID is used in file names, e.g. for the leaderboard of the mode.
+	ID() string
+	Name() string
+	// Setup is called after the world has been reset for a new run.
+	Setup(game *Game)
+	// Update applies the rules of the mode for one tick and reports whether the rest of the tick is skipped,
+	// e.g. while a LAN race waits for the inputs of the others. It doesn't end the run, Finished does.
+	Update(game *Game) bool
+	// Collision is called when the player hits traffic or a lethal hazard and reports whether it is a crash.
+	Collision(game *Game, cause string) bool
+	// Points returns the points earned in this tick.
+	Points(game *Game) float64
+	// Finished reports whether the run is over without a crash, e.g. because the time is up.
+	Finished(game *Game) bool
+	DrawHUD(game *Game, screen *ebiten.Image, textFace text.Face)
+}
+
+const pointsPerTick = 0.1
+
+// newModes returns the modes in the order they are picked from, the campaign and LAN races are started elsewhere.
+func newModes() []Mode {
+	return []Mode{&endlessMode{}, &timeAttackMode{}, &zenMode{}, &pursuitMode{}, &versusMode{}}
+}
+
+type endlessMode struct{}
+
+func (mode *endlessMode) ID() string {
+	return "endless"
+}
+
+func (mode *endlessMode) Name() string {
+	return "Endless"
+}
+
+func (mode *endlessMode) Setup(game *Game) {}
+
+func (mode *endlessMode) Update(game *Game) bool {
+	return false
+}
+
+func (mode *endlessMode) Collision(game *Game, cause string) bool {
+	return true
+}
+
+func (mode *endlessMode) Points(game *Game) float64 {
+	return pointsPerTick
+}
+
+func (mode *endlessMode) Finished(game *Game) bool {
+	return false
+}
+
+func (mode *endlessMode) DrawHUD(game *Game, screen *ebiten.Image, textFace text.Face) {}
+
+const timeAttackTicks = 2 * 60 * 60 // two minutes
+
+type timeAttackMode struct {
+	ticks int
+}
+
+func (mode *timeAttackMode) ID() string {
+	return "time_attack"
+}
+
+func (mode *timeAttackMode) Name() string {
+	return "Time attack"
+}
+
+func (mode *timeAttackMode) Setup(game *Game) {
+	mode.ticks = 0
+}
+
+func (mode *timeAttackMode) Update(game *Game) bool {
+	mode.ticks++
+	return false
+}
+
+func (mode *timeAttackMode) Collision(game *Game, cause string) bool {
+	return true
+}
+
+// Points rewards driving high on the screen, which is driving fast.
+func (mode *timeAttackMode) Points(game *Game) float64 {
+	return pointsPerTick * (2 - game.player.Y/game.windowHeight)
+}
+
+func (mode *timeAttackMode) Finished(game *Game) bool {
+	return mode.ticks >= timeAttackTicks
+}
+
+func (mode *timeAttackMode) DrawHUD(game *Game, screen *ebiten.Image, textFace text.Face) {
+	seconds := max(timeAttackTicks-mode.ticks, 0) / 60
+	drawHUDText(screen, fmt.Sprintf("Time: %d:%02d", seconds/60, seconds%60), textFace, 20, 20)
+}
+
+type zenMode struct{}
+
+func (mode *zenMode) ID() string {
+	return "zen"
+}
+
+func (mode *zenMode) Name() string {
+	return "Zen"
+}
+
+func (mode *zenMode) Setup(game *Game) {}
+
+func (mode *zenMode) Update(game *Game) bool {
+	return false
+}
+
+func (mode *zenMode) Collision(game *Game, cause string) bool {
+	return false
+}
+
+func (mode *zenMode) Points(game *Game) float64 {
+	return pointsPerTick
+}
+
+func (mode *zenMode) Finished(game *Game) bool {
+	return false
+}
+
+func (mode *zenMode) DrawHUD(game *Game, screen *ebiten.Image, textFace text.Face) {}
+
+func drawHUDText(screen *ebiten.Image, label string, textFace text.Face, x, y float64) {
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(x, y)
+	op.ColorScale.Scale(0, 0, 0, 1)
+	text.Draw(screen, label, textFace, op)
+}
@@ -0,0 +1,81 @@
+package game
+
+import (
+	"time"
+
+	"github.com/VxVxN/game/pkg/replay"
+	"github.com/VxVxN/game/pkg/statisticer"
+)
+
+// Version is sent with the scores to the online leaderboard, release builds set it with -ldflags.
+var Version = "dev"
+
+const (
+	onlineTopSize = 10
+	// onlineTopMaxAge is how long a top list is shown before the ratings page loads it again
+	onlineTopMaxAge = time.Minute
+)
+
+// onlineTop is the global top list of a mode as the ratings page shows it.
+type onlineTop struct {
+	mode    string
+	records []statisticer.Record
+	err     error
+	loaded  bool
+	at      time.Time
+}
+
+// difficulty describes how hard the run was. There are no difficulty levels, the steering speed is what
+// makes the same seed easier or harder.
+func (game *Game) difficulty() string {
+	return replay.Difficulty(game.settings.RawSettings.CarSensitivity)
+}
+
+// submitRecord sends the record to the online leaderboard without holding up the game,
+// it is queued on disk while the leaderboard can't be reached.
+func (game *Game) submitRecord(record statisticer.Record) {
+	if game.onlineLeaderboard == nil {
+		return
+	}
+	if record.Replay != nil {
+		if err := record.Replay.Validate(); err != nil {
+			game.logger.Info("The record isn't submitted, the server can't verify it", "reason", err)
+			return
+		}
+	}
+	go func() {
+		if err := game.onlineLeaderboard.Submit(record); err != nil {
+			game.logger.Error("Failed to submit the record", "error", err)
+		}
+	}()
+}
+
+// fetchOnlineTop loads the global top list of the mode, updateOnlineTop picks it up once it has arrived.
+func (game *Game) fetchOnlineTop(mode string) {
+	if game.onlineLeaderboard == nil || game.onlineTop.mode == mode && time.Since(game.onlineTop.at) < onlineTopMaxAge {
+		return
+	}
+	game.onlineTop = onlineTop{mode: mode, at: time.Now()}
+	go func() {
+		records, err := game.onlineLeaderboard.Top(mode, onlineTopSize)
+		game.onlineMutex.Lock()
+		game.fetchedTop = &onlineTop{mode: mode, records: records, err: err, loaded: true, at: time.Now()}
+		game.onlineMutex.Unlock()
+	}()
+}
+
+// updateOnlineTop reports whether the top list of the shown mode has arrived.
+func (game *Game) updateOnlineTop() bool {
+	game.onlineMutex.Lock()
+	top := game.fetchedTop
+	game.fetchedTop = nil
+	game.onlineMutex.Unlock()
+	if top == nil || top.mode != game.onlineTop.mode {
+		return false // the player has already switched to another mode
+	}
+	if top.err != nil {
+		game.logger.Error("Failed to load the online leaderboard", "error", top.err)
+	}
+	game.onlineTop = *top
+	return true
+}
@@ -0,0 +1,65 @@
+package game
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/VxVxN/game/internal/datadir"
+	"github.com/VxVxN/game/internal/settings"
+	"github.com/VxVxN/game/internal/stager"
+	"github.com/VxVxN/game/pkg/replay"
+)
+
+// Options are given at launch, they change the game until it is closed and are never saved.
+type Options struct {
+	DataDir  datadir.Dir
+	Session  settings.Session
+	Seed     *uint64 // every run is driven on the seed, a new one for every run if it is nil
+	Mode     string  // the ID of the mode a run is started in right away
+	Profile  string  // the name or the ID of the profile that is played instead of picking one
+	LogLevel slog.Level
+	Replay   *replay.Replay // the run that is shown right away
+}
+
+// launch applies the options once the game is set up.
+func (game *Game) launch(options Options) error {
+	game.fixedSeed = options.Seed
+	if options.Replay != nil {
+		// the world is laid out for the window the run was driven in
+		options.Session.Resolution = settings.Resolution(fmt.Sprintf("%.0fx%.0f", options.Replay.Width, options.Replay.Height))
+	}
+	game.settings.SetSession(options.Session)
+	game.ApplySettings()
+
+	if options.Profile != "" {
+		player := game.profiles.Find(options.Profile)
+		if player == nil {
+			return fmt.Errorf("there is no profile %q", options.Profile)
+		}
+		game.useProfile(player)
+		game.stager.SetStage(stager.MainMenuStage)
+	}
+	switch {
+	case options.Replay != nil:
+		return game.watch(options.Replay)
+	case options.Mode != "":
+		mode, err := findMode(game.modes, options.Mode)
+		if err != nil {
+			return err
+		}
+		game.StartMode(mode)
+	}
+	return nil
+}
+
+func findMode(modes []Mode, id string) (Mode, error) {
+	ids := make([]string, len(modes))
+	for i, mode := range modes {
+		if mode.ID() == id {
+			return mode, nil
+		}
+		ids[i] = mode.ID()
+	}
+	return nil, fmt.Errorf("there is no mode %q, the modes are %s", id, strings.Join(ids, ", "))
+}
@@ -2,16 +2,21 @@ package game
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"strconv"
 
 	"github.com/ebitenui/ebitenui"
 	"github.com/ebitenui/ebitenui/widget"
+	"github.com/hajimehoshi/ebiten/v2"
 
+	"github.com/VxVxN/game/internal/achievements"
+	"github.com/VxVxN/game/internal/input"
 	"github.com/VxVxN/game/internal/settings"
+	"github.com/VxVxN/game/internal/shop"
 	"github.com/VxVxN/game/internal/stager"
 	"github.com/VxVxN/game/internal/ui"
+	"github.com/VxVxN/game/pkg/replay"
+	"github.com/VxVxN/game/pkg/statisticer"
 )
 
 type mainUI struct {
@@ -36,19 +41,85 @@ func newMainUI(game *Game, res *ui.UiResources) *mainUI {
 		widget.ButtonOpts.Text("New game", res.Button.Face, res.Button.Text),
 		widget.ButtonOpts.TextPadding(res.Button.Padding),
 		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
-			game.Reset()
+			game.stager.SetStage(stager.ModeSelectStage)
 		}))
 	container.AddChild(newGameButton)
 
+	campaignButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Campaign", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.stager.SetStage(stager.LevelSelectStage)
+		}))
+	container.AddChild(campaignButton)
+
+	lanButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("LAN game", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.stager.SetStage(stager.LobbyStage)
+		}))
+	container.AddChild(lanButton)
+
+	editorButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Level editor", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.stager.SetStage(stager.EditorStage)
+		}))
+	container.AddChild(editorButton)
+
+	garageButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Garage", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.stager.SetStage(stager.GarageStage)
+		}))
+	container.AddChild(garageButton)
+
+	shopButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Shop", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.stager.SetStage(stager.ShopStage)
+		}))
+	container.AddChild(shopButton)
+
 	playerRatingsButton := widget.NewButton(
 		buttonOpts,
 		widget.ButtonOpts.Image(res.Button.Image),
 		widget.ButtonOpts.Text("Player ratings", res.Button.Face, res.Button.Text),
 		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			if game.leaderboard() != nil {
+				game.ratingsMode = game.mode
+			}
 			game.stager.SetStage(stager.StatisticsStage)
 		}))
 	container.AddChild(playerRatingsButton)
 
+	achievementsButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Achievements", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.stager.SetStage(stager.AchievementsStage)
+		}))
+	container.AddChild(achievementsButton)
+
+	profilesButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Profiles", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.stager.SetStage(stager.ProfilesStage)
+		}))
+	container.AddChild(profilesButton)
+
 	settingsButton := widget.NewButton(
 		buttonOpts,
 		widget.ButtonOpts.Image(res.Button.Image),
@@ -69,7 +140,7 @@ func newMainUI(game *Game, res *ui.UiResources) *mainUI {
 
 	return &mainUI{
 		widget:  container,
-		buttons: ui.NewButtonControl([]*widget.Button{newGameButton, playerRatingsButton, settingsButton, exitButton}),
+		buttons: ui.NewButtonControl([]*widget.Button{newGameButton, campaignButton, lanButton, editorButton, garageButton, shopButton, playerRatingsButton, achievementsButton, profilesButton, settingsButton, exitButton}),
 	}
 }
 
@@ -138,20 +209,15 @@ type playerRatingsUI struct {
 	footerText *widget.Text
 }
 
-func newPlayerRatingsUI(game *Game, res *ui.UiResources) *playerRatingsUI {
+func newPlayerRatingsUI(game *Game, res *ui.UiResources, records []statisticer.Record) *playerRatingsUI {
 	container := ui.NewPageContentContainer()
 
-	records, err := game.statisticer.Load()
-	if err != nil {
-		log.Fatalf("Failed to load statistics: %v", err)
-	}
-
 	gridLayoutContainer := widget.NewContainer(
 		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
 			Position: widget.RowLayoutPositionCenter,
 		})),
 		widget.ContainerOpts.Layout(widget.NewGridLayout(
-			widget.GridLayoutOpts.Columns(2),
+			widget.GridLayoutOpts.Columns(3),
 			widget.GridLayoutOpts.Stretch([]bool{true, true, true, true}, nil),
 			widget.GridLayoutOpts.Spacing(10, 10))))
 	container.AddChild(gridLayoutContainer)
@@ -162,12 +228,22 @@ func newPlayerRatingsUI(game *Game, res *ui.UiResources) *playerRatingsUI {
 	gridLayoutContainer.AddChild(widget.NewText(
 		widget.TextOpts.Text("Points", res.Text.TitleFace, res.Text.IdleColor)))
 
+	gridLayoutContainer.AddChild(widget.NewText(
+		widget.TextOpts.Text("Biome", res.Text.TitleFace, res.Text.IdleColor)))
+
 	for _, record := range records {
 		gridLayoutContainer.AddChild(widget.NewText(
 			widget.TextOpts.Text(record.Name, res.Text.Face, res.Text.IdleColor)))
 
 		gridLayoutContainer.AddChild(widget.NewText(
 			widget.TextOpts.Text(strconv.Itoa(record.Points), res.Text.Face, res.Text.IdleColor)))
+
+		gridLayoutContainer.AddChild(widget.NewText(
+			widget.TextOpts.Text(record.Biome, res.Text.Face, res.Text.IdleColor)))
+	}
+
+	if game.onlineLeaderboard != nil {
+		addOnlineTop(game, res, gridLayoutContainer)
 	}
 
 	textContainer := widget.NewContainer(
@@ -176,25 +252,787 @@ func newPlayerRatingsUI(game *Game, res *ui.UiResources) *playerRatingsUI {
 	)
 
 	text := widget.NewText(
-		widget.TextOpts.Text("Press Enter to exit", res.Text.Face, res.Text.DisabledColor))
+		widget.TextOpts.Text(fmt.Sprintf("Press %s/%s to switch the mode\nPress %s for the charts\nPress %s to exit",
+			game.controlName(input.MenuLeft), game.controlName(input.MenuRight), game.controlName(input.SwitchView), game.controlName(input.Confirm)),
+			res.Text.Face, res.Text.DisabledColor))
 	textContainer.AddChild(text)
 
 	gridLayoutContainer.AddChild(textContainer)
 
+	container.AddChild(newClickRow(res,
+		clickButton{"Previous mode", func() { game.switchRatingsMode(-1) }},
+		clickButton{"Next mode", func() { game.switchRatingsMode(1) }},
+		clickButton{"Charts", func() {
+			game.stager.SetStage(stager.DashboardStage)
+			game.dashboard.Show(game.ratingsMode.Name(), game.profile.Name)
+		}},
+		clickButton{"Back", func() { game.stager.SetStage(stager.MainMenuStage) }}))
+
 	return &playerRatingsUI{
 		widget: container,
 	}
 }
 
-type setPlayerRatingUI struct {
+type clickButton struct {
+	label   string
+	pressed func()
+}
+
+// newClickRow returns a row of buttons for the pages that are used with the keys, so they can be used
+// with the mouse and by touch as well.
+func newClickRow(res *ui.UiResources, buttons ...clickButton) *widget.Container {
+	row := widget.NewContainer(
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Position: widget.RowLayoutPositionCenter,
+		})),
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Padding(widget.NewInsetsSimple(20)),
+			widget.RowLayoutOpts.Spacing(5))))
+	for _, button := range buttons {
+		row.AddChild(widget.NewButton(
+			widget.ButtonOpts.TextPadding(widget.Insets{
+				Left:  30,
+				Right: 30,
+			}),
+			widget.ButtonOpts.Image(res.Button.Image),
+			widget.ButtonOpts.Text(button.label, res.Button.Face, res.Button.Text),
+			widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+				button.pressed()
+			})))
+	}
+	return row
+}
+
+type achievementsUI struct {
+	widget     widget.PreferredSizeLocateableWidget
+	ui         *ebitenui.UI
+	footerText *widget.Text
+}
+
+func newAchievementsUI(game *Game, res *ui.UiResources) *achievementsUI {
+	container := ui.NewPageContentContainer()
+
+	gridLayoutContainer := widget.NewContainer(
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Position: widget.RowLayoutPositionCenter,
+		})),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(3),
+			widget.GridLayoutOpts.Stretch([]bool{true, true, true}, nil),
+			widget.GridLayoutOpts.Spacing(30, 10))))
+	container.AddChild(gridLayoutContainer)
+
+	for _, achievement := range achievements.All {
+		textColor := res.Text.DisabledColor
+		status := "Locked"
+		if current, goal := achievement.Progress(game.achievements); goal > 1 {
+			status = fmt.Sprintf("%d/%d", current, goal)
+		}
+		if at, ok := game.achievements.Unlocked[achievement.ID]; ok {
+			textColor = res.Text.IdleColor
+			status = at.Format("2006-01-02")
+		}
+		gridLayoutContainer.AddChild(widget.NewText(
+			widget.TextOpts.Text(achievement.Name, res.Text.TitleFace, textColor)))
+		gridLayoutContainer.AddChild(widget.NewText(
+			widget.TextOpts.Text(achievement.Description, res.Text.Face, textColor)))
+		gridLayoutContainer.AddChild(widget.NewText(
+			widget.TextOpts.Text(status, res.Text.Face, textColor)))
+	}
+
+	textContainer := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Padding(widget.NewInsetsSimple(100)))),
+	)
+	textContainer.AddChild(widget.NewText(
+		widget.TextOpts.Text("Press "+game.controlName(input.Confirm)+" to exit", res.Text.Face, res.Text.DisabledColor)))
+	container.AddChild(textContainer)
+
+	container.AddChild(newClickRow(res, clickButton{"Back", func() { game.stager.SetStage(stager.MainMenuStage) }}))
+
+	return &achievementsUI{widget: container}
+}
+
+// addOnlineTop adds the global top list of the mode under the local one.
+func addOnlineTop(game *Game, res *ui.UiResources, container *widget.Container) {
+	status := ""
+	switch {
+	case !game.onlineTop.loaded:
+		status = "Loading..."
+	case game.onlineTop.err != nil:
+		status = "Offline"
+	case len(game.onlineTop.records) == 0:
+		status = "No records yet"
+	}
+	container.AddChild(widget.NewText(
+		widget.TextOpts.Text("Online", res.Text.TitleFace, res.Text.IdleColor)))
+	container.AddChild(widget.NewText(
+		widget.TextOpts.Text(status, res.Text.Face, res.Text.DisabledColor)))
+	container.AddChild(widget.NewText(
+		widget.TextOpts.Text("", res.Text.Face, res.Text.IdleColor)))
+
+	for _, record := range game.onlineTop.records {
+		container.AddChild(widget.NewText(
+			widget.TextOpts.Text(record.Name, res.Text.Face, res.Text.IdleColor)))
+
+		container.AddChild(widget.NewText(
+			widget.TextOpts.Text(strconv.Itoa(record.Points), res.Text.Face, res.Text.IdleColor)))
+
+		container.AddChild(widget.NewText(
+			widget.TextOpts.Text(record.Biome, res.Text.Face, res.Text.IdleColor)))
+	}
+}
+
+type modeSelectUI struct {
+	widget     widget.PreferredSizeLocateableWidget
+	ui         *ebitenui.UI
+	buttons    *ui.ButtonControl
+	footerText *widget.Text
+}
+
+func newModeSelectUI(game *Game, res *ui.UiResources) *modeSelectUI {
+	container := ui.NewPageContentContainer()
+
+	buttonOpts := widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+		Position: widget.RowLayoutPositionCenter,
+		MaxWidth: 300,
+		Stretch:  true,
+	}))
+
+	var buttons []*widget.Button
+	for _, mode := range game.modes {
+		button := widget.NewButton(
+			buttonOpts,
+			widget.ButtonOpts.Image(res.Button.Image),
+			widget.ButtonOpts.Text(mode.Name(), res.Button.Face, res.Button.Text),
+			widget.ButtonOpts.TextPadding(res.Button.Padding),
+			widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+				game.StartMode(mode)
+			}))
+		container.AddChild(button)
+		buttons = append(buttons, button)
+	}
+
+	backButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Back", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.stager.SetStage(stager.MainMenuStage)
+		}))
+	container.AddChild(backButton)
+
+	return &modeSelectUI{
+		widget:  container,
+		buttons: ui.NewButtonControl(append(buttons, backButton)),
+	}
+}
+
+type levelSelectUI struct {
+	widget     widget.PreferredSizeLocateableWidget
+	ui         *ebitenui.UI
+	buttons    *ui.ButtonControl
+	footerText *widget.Text
+}
+
+func newLevelSelectUI(game *Game, res *ui.UiResources) *levelSelectUI {
+	container := ui.NewPageContentContainer()
+
+	buttonOpts := widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+		Position: widget.RowLayoutPositionCenter,
+		MaxWidth: 500,
+		Stretch:  true,
+	}))
+
+	var buttons []*widget.Button
+	for i, level := range game.levels {
+		unlocked := game.unlocks.Unlocked(game.profile.ID, game.levels, i)
+		label := fmt.Sprintf("%d. %s - locked", i+1, level.Name)
+		if unlocked {
+			label = fmt.Sprintf("%d. %s - stars: %d/3", i+1, level.Name, game.unlocks.Stars(game.profile.ID, level.ID))
+		}
+		button := widget.NewButton(
+			buttonOpts,
+			widget.ButtonOpts.Image(res.Button.Image),
+			widget.ButtonOpts.Text(label, res.Button.Face, res.Button.Text),
+			widget.ButtonOpts.TextPadding(res.Button.Padding),
+			widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+				if !unlocked {
+					return
+				}
+				game.StartMode(&campaignMode{level: level})
+			}))
+		button.GetWidget().Disabled = !unlocked
+		container.AddChild(button)
+		buttons = append(buttons, button)
+	}
+
+	backButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Back", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.stager.SetStage(stager.MainMenuStage)
+		}))
+	container.AddChild(backButton)
+
+	return &levelSelectUI{
+		widget:  container,
+		buttons: ui.NewButtonControl(append(buttons, backButton)),
+	}
+}
+
+type lobbyUI struct {
+	widget       widget.PreferredSizeLocateableWidget
+	ui           *ebitenui.UI
+	buttons      *ui.ButtonControl
+	footerText   *widget.Text
+	addressInput *widget.TextInput
+	status       *widget.Text
+	message      string
+}
+
+func newLobbyUI(game *Game, res *ui.UiResources) *lobbyUI {
+	container := ui.NewPageContentContainer()
+	lobby := &lobbyUI{widget: container}
+
+	buttonOpts := widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+		Position: widget.RowLayoutPositionCenter,
+		MaxWidth: 300,
+		Stretch:  true,
+	}))
+
+	lobby.addressInput = widget.NewTextInput(
+		widget.TextInputOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Position: widget.RowLayoutPositionCenter,
+			MaxWidth: 300,
+			Stretch:  true,
+		})),
+		widget.TextInputOpts.Image(res.TextInput.Image),
+		widget.TextInputOpts.Color(res.TextInput.Color),
+		widget.TextInputOpts.Padding(widget.Insets{
+			Left:   13,
+			Right:  13,
+			Top:    7,
+			Bottom: 7,
+		}),
+		widget.TextInputOpts.Face(res.TextInput.Face),
+		widget.TextInputOpts.CaretOpts(
+			widget.CaretOpts.Size(res.TextInput.Face, 2),
+		),
+		widget.TextInputOpts.Placeholder("Address of the host"),
+	)
+	lobby.addressInput.SetText(lanAddress)
+	container.AddChild(lobby.addressInput)
+
+	hostButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Host", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			lobby.message = ""
+			if err := game.hostRace(lobby.addressInput.GetText()); err != nil {
+				lobby.message = err.Error()
+			}
+		}))
+	container.AddChild(hostButton)
+
+	joinButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Join", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			lobby.message = ""
+			if err := game.joinRace(lobby.addressInput.GetText()); err != nil {
+				lobby.message = err.Error()
+			}
+		}))
+	container.AddChild(joinButton)
+
+	startButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Start", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			if game.session == nil || !game.session.IsHost() {
+				return
+			}
+			game.startRace()
+		}))
+	container.AddChild(startButton)
+
+	backButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Back", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.closeSession()
+			game.stager.SetStage(stager.MainMenuStage)
+		}))
+	container.AddChild(backButton)
+
+	lobby.status = widget.NewText(
+		widget.TextOpts.Text("", res.Text.Face, res.Text.IdleColor))
+	container.AddChild(lobby.status)
+
+	lobby.buttons = ui.NewButtonControl([]*widget.Button{hostButton, joinButton, startButton, backButton})
+	return lobby
+}
+
+type recoverRecordsUI struct {
+	widget     widget.PreferredSizeLocateableWidget
+	ui         *ebitenui.UI
+	buttons    *ui.ButtonControl
+	footerText *widget.Text
+	text       *widget.Text
+}
+
+func newRecoverRecordsUI(game *Game, res *ui.UiResources) *recoverRecordsUI {
+	container := ui.NewPageContentContainer()
+	recoverUI := &recoverRecordsUI{widget: container}
+
+	buttonOpts := widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+		Position: widget.RowLayoutPositionCenter,
+		MaxWidth: 450,
+		Stretch:  true,
+	}))
+
+	recoverUI.text = widget.NewText(
+		widget.TextOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Position: widget.RowLayoutPositionCenter,
+		})),
+		widget.TextOpts.Text("", res.Text.Face, res.Text.IdleColor))
+	container.AddChild(recoverUI.text)
+
+	restoreButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Restore the backup", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			if err := game.recovering.RestoreBackup(); err != nil {
+				recoverUI.text.Label = fmt.Sprintf("The backup can't be restored: %v", err)
+				return
+			}
+			game.afterRecovery()
+		}))
+	container.AddChild(restoreButton)
+
+	resetButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Start over", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			if err := game.recovering.Reset(); err != nil {
+				recoverUI.text.Label = fmt.Sprintf("The file can't be reset: %v", err)
+				return
+			}
+			game.afterRecovery()
+		}))
+	container.AddChild(resetButton)
+
+	backButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Go back to the main menu", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.stager.SetStage(stager.MainMenuStage)
+		}))
+	container.AddChild(backButton)
+
+	recoverUI.buttons = ui.NewButtonControl([]*widget.Button{restoreButton, resetButton, backButton})
+	return recoverUI
+}
+
+type profilesUI struct {
+	widget     widget.PreferredSizeLocateableWidget
+	ui         *ebitenui.UI
+	buttons    *ui.ButtonControl
+	footerText *widget.Text
+	status     *widget.Text
+}
+
+func newProfilesUI(game *Game, res *ui.UiResources) *profilesUI {
+	container := ui.NewPageContentContainer()
+	profiles := &profilesUI{widget: container}
+
+	buttonOpts := widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+		Position: widget.RowLayoutPositionCenter,
+		MaxWidth: 500,
+		Stretch:  true,
+	}))
+
+	var buttons []*widget.Button
+	for _, player := range game.profiles.Profiles {
+		label := player.Name
+		if player == game.profile {
+			label += " - playing"
+		}
+		button := widget.NewButton(
+			buttonOpts,
+			widget.ButtonOpts.Image(res.Button.Image),
+			widget.ButtonOpts.Text(label, res.Button.Face, res.Button.Text),
+			widget.ButtonOpts.TextPadding(res.Button.Padding),
+			widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+				game.useProfile(player)
+				game.stager.SetStage(stager.MainMenuStage)
+			}))
+		container.AddChild(button)
+		buttons = append(buttons, button)
+	}
+
+	newButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("New profile", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.renaming = nil
+			game.stager.SetStage(stager.ProfileNameStage)
+		}))
+	container.AddChild(newButton)
+
+	renameButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Rename "+game.profile.Name, res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.renaming = game.profile
+			game.stager.SetStage(stager.ProfileNameStage)
+		}))
+	container.AddChild(renameButton)
+
+	var confirmed bool
+	deleteButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Delete "+game.profile.Name, res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			if !confirmed {
+				confirmed = true
+				profiles.status.Label = fmt.Sprintf("Press again to delete %s with all records and progress", game.profile.Name)
+				return
+			}
+			if err := game.deleteProfile(game.profile); err != nil {
+				profiles.status.Label = fmt.Sprintf("The profile can't be deleted: %v", err)
+				return
+			}
+			game.changeUIByStage[stager.ProfilesStage]()
+		}))
+	container.AddChild(deleteButton)
+
+	backButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Back", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.stager.SetStage(stager.MainMenuStage)
+		}))
+	container.AddChild(backButton)
+
+	profiles.status = widget.NewText(
+		widget.TextOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Position: widget.RowLayoutPositionCenter,
+		})),
+		widget.TextOpts.Text("", res.Text.Face, res.Text.IdleColor))
+	container.AddChild(profiles.status)
+
+	profiles.buttons = ui.NewButtonControl(append(buttons, newButton, renameButton, deleteButton, backButton))
+	return profiles
+}
+
+type garageUI struct {
+	widget     widget.PreferredSizeLocateableWidget
+	ui         *ebitenui.UI
+	buttons    *ui.ButtonControl
+	footerText *widget.Text
+}
+
+func newGarageUI(game *Game, res *ui.UiResources) *garageUI {
+	container := ui.NewPageContentContainer()
+
+	buttonOpts := widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+		Position: widget.RowLayoutPositionCenter,
+		MaxWidth: 900,
+		Stretch:  true,
+	}))
+
+	var buttons []*widget.Button
+	for _, vehicle := range game.vehicles {
+		label := vehicle.Name
+		switch {
+		case !game.owns(vehicle):
+			label += fmt.Sprintf(" - %d coins in the shop", vehicle.Price)
+		case vehicle.ID == game.chosenVehicle().ID:
+			label += " - driving"
+		}
+		handling := vehicle.Handling
+		label += fmt.Sprintf("\n%s\nSteering %.0f%%   Acceleration %.0f%%   Top speed %.0f%%   Brakes %.0f%%", vehicle.Description,
+			handling.Steering*100, handling.Acceleration*100, handling.TopSpeed*100, handling.Brakes*100)
+
+		// the sprites are as big as on the road, half the size fits on the page
+		sprite := game.vehicleImages[vehicle.ID].sprite
+		preview := ebiten.NewImage(sprite.Bounds().Dx()/2, sprite.Bounds().Dy()/2)
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(0.5, 0.5)
+		op.Filter = ebiten.FilterLinear
+		preview.DrawImage(sprite, op)
+
+		button := widget.NewButton(
+			buttonOpts,
+			widget.ButtonOpts.Image(res.Button.Image),
+			widget.ButtonOpts.TextAndImage(label, res.Button.Face, &widget.ButtonImageImage{Idle: preview, Disabled: preview}, res.Button.Text),
+			widget.ButtonOpts.TextPadding(res.Button.Padding),
+			widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+				if !game.owns(vehicle) {
+					game.stager.SetStage(stager.ShopStage)
+					return
+				}
+				game.chooseVehicle(vehicle)
+				game.stager.SetStage(stager.MainMenuStage)
+			}))
+		container.AddChild(button)
+		buttons = append(buttons, button)
+	}
+
+	backButton := widget.NewButton(
+		buttonOpts,
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Back", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.stager.SetStage(stager.MainMenuStage)
+		}))
+	container.AddChild(backButton)
+
+	return &garageUI{
+		widget:  container,
+		buttons: ui.NewButtonControl(append(buttons, backButton)),
+	}
+}
+
+type shopUI struct {
+	widget     widget.PreferredSizeLocateableWidget
+	ui         *ebitenui.UI
+	buttons    *ui.ButtonControl
+	footerText *widget.Text
+	status     *widget.Text
+}
+
+func newShopUI(game *Game, res *ui.UiResources) *shopUI {
+	container := ui.NewPageContentContainer()
+	page := &shopUI{widget: container}
+	purchases := &game.profile.Purchases
+
+	container.AddChild(widget.NewText(
+		widget.TextOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Position: widget.RowLayoutPositionCenter,
+		})),
+		widget.TextOpts.Text(fmt.Sprintf("%s has %d coins", game.profile.Name, purchases.Coins), res.Text.TitleFace, res.Text.IdleColor)))
+
+	columns := widget.NewContainer(
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Position: widget.RowLayoutPositionCenter,
+		})),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(3),
+			widget.GridLayoutOpts.Stretch([]bool{true, true, true}, nil),
+			widget.GridLayoutOpts.Spacing(30, 10))))
+	container.AddChild(columns)
+
+	// bought runs after a purchase, the page is built again to show it
+	bought := func(err error, done string) {
+		if err != nil {
+			page.status.Label = "Can't buy it: " + err.Error()
+			return
+		}
+		game.changeUIByStage[stager.ShopStage]()
+		game.shopUI.status.Label = done
+	}
+	var buttons []*widget.Button
+	addColumn := func(title string, labels []string, pressed []func()) {
+		column := ui.NewPageContentContainer()
+		column.AddChild(widget.NewText(
+			widget.TextOpts.Text(title, res.Text.TitleFace, res.Text.IdleColor)))
+		for i, label := range labels {
+			button := widget.NewButton(
+				widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{Stretch: true})),
+				widget.ButtonOpts.Image(res.Button.Image),
+				widget.ButtonOpts.Text(label, res.Button.Face, res.Button.Text),
+				widget.ButtonOpts.TextPadding(res.Button.Padding),
+				widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+					pressed[i]()
+				}))
+			column.AddChild(button)
+			buttons = append(buttons, button)
+		}
+		columns.AddChild(column)
+	}
+
+	var labels []string
+	var pressed []func()
+	for _, vehicle := range game.vehicles {
+		if vehicle.Price == 0 {
+			continue
+		}
+		if game.owns(vehicle) {
+			labels = append(labels, vehicle.Name+" - in the garage")
+			pressed = append(pressed, func() {
+				game.stager.SetStage(stager.GarageStage)
+			})
+			continue
+		}
+		labels = append(labels, fmt.Sprintf("%s - %d coins", vehicle.Name, vehicle.Price))
+		pressed = append(pressed, func() {
+			bought(game.buyVehicle(vehicle), vehicle.Name+" is in the garage now")
+		})
+	}
+	addColumn("Vehicles", labels, pressed)
+
+	labels, pressed = nil, nil
+	for _, paint := range shop.Paints {
+		label := fmt.Sprintf("%s - %d coins", paint.Name, paint.Price)
+		switch {
+		case paint.ID == shop.FindPaint(game.profile.Paint).ID:
+			label = paint.Name + " - on the car"
+		case purchases.OwnsPaint(paint):
+			label = paint.Name
+		}
+		labels = append(labels, label)
+		pressed = append(pressed, func() {
+			bought(game.paint(paint), "The car is painted "+paint.Name)
+		})
+	}
+	addColumn("Paint", labels, pressed)
+
+	labels, pressed = nil, nil
+	for _, upgrade := range shop.Upgrades {
+		label := fmt.Sprintf("%s %d/%d - top level", upgrade.Name, purchases.Level(upgrade), len(upgrade.Prices))
+		if price, ok := purchases.NextPrice(upgrade); ok {
+			label = fmt.Sprintf("%s %d/%d - %d coins\n%s", upgrade.Name, purchases.Level(upgrade), len(upgrade.Prices), price, upgrade.Description)
+		}
+		labels = append(labels, label)
+		pressed = append(pressed, func() {
+			bought(game.buy(func(purchases *shop.Purchases) error {
+				return purchases.BuyUpgrade(upgrade)
+			}), upgrade.Name+" is upgraded")
+		})
+	}
+	addColumn("Upgrades", labels, pressed)
+
+	backButton := widget.NewButton(
+		widget.ButtonOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Position: widget.RowLayoutPositionCenter,
+			MaxWidth: 300,
+			Stretch:  true,
+		})),
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Back", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.stager.SetStage(stager.MainMenuStage)
+		}))
+	container.AddChild(backButton)
+
+	page.status = widget.NewText(
+		widget.TextOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Position: widget.RowLayoutPositionCenter,
+		})),
+		widget.TextOpts.Text("Runs pay coins for the distance and the near misses", res.Text.Face, res.Text.IdleColor))
+	container.AddChild(page.status)
+
+	page.buttons = ui.NewButtonControl(append(buttons, backButton))
+	return page
+}
+
+type controlsUI struct {
+	widget     widget.PreferredSizeLocateableWidget
+	ui         *ebitenui.UI
+	buttons    *ui.ButtonControl
+	footerText *widget.Text
+	status     *widget.Text
+}
+
+func newControlsUI(game *Game, res *ui.UiResources) *controlsUI {
+	container := ui.NewPageContentContainer()
+	page := &controlsUI{widget: container}
+	bindings := game.settings.SavedSettings.Bindings
+
+	page.status = widget.NewText(
+		widget.TextOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Position: widget.RowLayoutPositionCenter,
+		})),
+		widget.TextOpts.Text("Choose an action to bind it to a key, a gamepad input or a mouse button\nIt replaces the binding of the same device", res.Text.Face, res.Text.IdleColor))
+	container.AddChild(page.status)
+
+	gridLayoutContainer := widget.NewContainer(
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Position: widget.RowLayoutPositionCenter,
+		})),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(2),
+			widget.GridLayoutOpts.Stretch([]bool{true, true}, nil),
+			widget.GridLayoutOpts.Spacing(10, 10))))
+	container.AddChild(gridLayoutContainer)
+
+	var buttons []*widget.Button
+	for _, action := range input.Actions() {
+		button := widget.NewButton(
+			widget.ButtonOpts.Image(res.Button.Image),
+			widget.ButtonOpts.Text(action.Title()+": "+bindings.Titles(action), res.Button.Face, res.Button.Text),
+			widget.ButtonOpts.TextPadding(res.Button.Padding),
+			widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+				page.status.Label = fmt.Sprintf("Press what %s is bound to\nPress Escape to leave it as it is", action.Title())
+				game.listenForBinding(action, nil)
+			}))
+		gridLayoutContainer.AddChild(button)
+		buttons = append(buttons, button)
+	}
+
+	rowLayoutContainer := widget.NewContainer(
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
+			Position: widget.RowLayoutPositionCenter,
+		})),
+		widget.ContainerOpts.Layout(widget.NewRowLayout(
+			widget.RowLayoutOpts.Padding(widget.NewInsetsSimple(20)),
+			widget.RowLayoutOpts.Spacing(5))))
+	container.AddChild(rowLayoutContainer)
+
+	resetButton := widget.NewButton(
+		widget.ButtonOpts.TextPadding(widget.Insets{
+			Left:  30,
+			Right: 30,
+		}),
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Reset to defaults", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.eventManager.StopListening()
+			game.setBindings(input.DefaultBindings(), "Every action is bound as it was at first")
+		}))
+	rowLayoutContainer.AddChild(resetButton)
+
+	backButton := widget.NewButton(
+		widget.ButtonOpts.TextPadding(widget.Insets{
+			Left:  30,
+			Right: 30,
+		}),
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Back", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.leaveControls()
+		}))
+	rowLayoutContainer.AddChild(backButton)
+
+	page.buttons = ui.NewButtonControl(append(buttons, resetButton, backButton))
+	return page
+}
+
+type profileNameUI struct {
 	widget     widget.PreferredSizeLocateableWidget
 	textInput  *widget.TextInput
 	ui         *ebitenui.UI
 	text       *widget.Text
+	status     *widget.Text
 	footerText *widget.Text
 }
 
-func newSetPlayerRatingUI(game *Game, res *ui.UiResources) *setPlayerRatingUI {
+func newProfileNameUI(res *ui.UiResources) *profileNameUI {
 	container := ui.NewPageContentContainer()
 
 	gridLayoutContainer := widget.NewContainer(
@@ -208,7 +1046,7 @@ func newSetPlayerRatingUI(game *Game, res *ui.UiResources) *setPlayerRatingUI {
 	container.AddChild(gridLayoutContainer)
 
 	text := widget.NewText(
-		widget.TextOpts.Text(fmt.Sprintf("Your new record: %d", int(game.player.Points())), res.Text.TitleFace, res.Text.IdleColor))
+		widget.TextOpts.Text("", res.Text.TitleFace, res.Text.IdleColor))
 	gridLayoutContainer.AddChild(text)
 
 	tOpts := []widget.TextInputOpt{
@@ -231,16 +1069,21 @@ func newSetPlayerRatingUI(game *Game, res *ui.UiResources) *setPlayerRatingUI {
 
 	textInput := widget.NewTextInput(append(
 		tOpts,
-		widget.TextInputOpts.Placeholder("Enter text here"),
+		widget.TextInputOpts.Placeholder("Enter the name here"),
 		widget.TextInputOpts.AllowDuplicateSubmit(true))...,
 	)
 	textInput.Focus(true)
 	gridLayoutContainer.AddChild(textInput)
 
-	return &setPlayerRatingUI{
+	status := widget.NewText(
+		widget.TextOpts.Text("", res.Text.Face, res.Text.DisabledColor))
+	gridLayoutContainer.AddChild(status)
+
+	return &profileNameUI{
 		widget:    container,
 		textInput: textInput,
 		text:      text,
+		status:    status,
 	}
 }
 
@@ -253,6 +1096,9 @@ type settingsUI struct {
 	sliderEffectsVolume  *widget.Slider
 	sliderCarSensitivity *widget.Slider
 	listResolution       *widget.ListComboButton
+	listPointerSteering  *widget.ListComboButton
+	songsHelp            *widget.Text
+	from                 stager.Stage // the page the settings were opened from
 }
 
 func newSettingsUI(game *Game, res *ui.UiResources) *settingsUI {
@@ -272,11 +1118,12 @@ func newSettingsUI(game *Game, res *ui.UiResources) *settingsUI {
 		widget.ButtonOpts.Image(res.Button.Image),
 		widget.ButtonOpts.Text("Save", res.Button.Face, res.Button.Text),
 		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
-			game.stager.RecoveryLastStage()
+			game.leaveSettings()
 			game.settings.Save()
 			if err := game.settings.WriteToFile(); err != nil {
-				game.logger.Error("Error saving settings", "error", err)
-				return
+				game.logger.Error("Error saving settings", "error", err) // they are still used until the game is closed
+			} else {
+				game.saveProfiles() // the profile keeps the volumes and the sensitivity
 			}
 			game.ApplySettings()
 		}))
@@ -290,11 +1137,23 @@ func newSettingsUI(game *Game, res *ui.UiResources) *settingsUI {
 		widget.ButtonOpts.Image(res.Button.Image),
 		widget.ButtonOpts.Text("Back", res.Button.Face, res.Button.Text),
 		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
-			game.stager.RecoveryLastStage()
+			game.leaveSettings()
 			game.ApplySettings()
 		}))
 	rayLayoutContainer.AddChild(backButton)
 
+	controlsButton := widget.NewButton(
+		widget.ButtonOpts.TextPadding(widget.Insets{
+			Left:  30,
+			Right: 30,
+		}),
+		widget.ButtonOpts.Image(res.Button.Image),
+		widget.ButtonOpts.Text("Controls", res.Button.Face, res.Button.Text),
+		widget.ButtonOpts.PressedHandler(func(args *widget.ButtonPressedEventArgs) {
+			game.stager.SetStage(stager.ControlsStage)
+		}))
+	rayLayoutContainer.AddChild(controlsButton)
+
 	container.AddChild(ui.NewSeparator(res, widget.RowLayoutData{
 		Stretch: true,
 	}))
@@ -350,21 +1209,46 @@ func newSettingsUI(game *Game, res *ui.UiResources) *settingsUI {
 	SliderCarSensitivityContainer, sliderCarSensitivity := buildSliderCarSensitivity(game, res, gridLayoutContainer)
 	gridLayoutContainer.AddChild(SliderCarSensitivityContainer)
 
+	gridLayoutContainer.AddChild(widget.NewText(
+		widget.TextOpts.Text("Pointer steering", res.Text.Face, res.Text.IdleColor)))
+
+	pointerEntries := make([]interface{}, len(input.PointerModes))
+	for i, mode := range input.PointerModes {
+		pointerEntries[i] = string(mode)
+	}
+	listPointerSteering := ui.NewListComboButton(
+		pointerEntries,
+		func(e interface{}) string {
+			return e.(string)
+		},
+		func(e interface{}) string {
+			return e.(string)
+		},
+		func(args *widget.ListComboButtonEntrySelectedEventArgs) {
+			game.settings.RawSettings.PointerSteering = input.PointerMode(args.Entry.(string))
+		},
+		res)
+	listPointerSteering.SetSelectedEntry(string(game.settings.SavedSettings.PointerSteering))
+	gridLayoutContainer.AddChild(listPointerSteering)
+
 	container.AddChild(ui.NewSeparator(res, widget.RowLayoutData{
 		Stretch: true,
 	}))
 
-	container.AddChild(widget.NewText(
+	songsHelp := widget.NewText(
 		widget.TextOpts.Insets(widget.NewInsetsSimple(20)),
-		widget.TextOpts.Text("Press Z to switch to the previous song\nPress X to switch to the next song", res.Text.Face, res.Text.IdleColor)))
+		widget.TextOpts.Text(game.songsHelp(), res.Text.Face, res.Text.IdleColor))
+	container.AddChild(songsHelp)
 
 	return &settingsUI{
 		widget:               container,
-		buttons:              ui.NewButtonControl([]*widget.Button{saveButton, backButton}),
+		buttons:              ui.NewButtonControl([]*widget.Button{saveButton, backButton, controlsButton}),
 		sliderMusicVolume:    sliderMusicVolume,
 		sliderEffectsVolume:  sliderEffectsVolume,
 		sliderCarSensitivity: sliderCarSensitivity,
 		listResolution:       listResolution,
+		listPointerSteering:  listPointerSteering,
+		songsHelp:            songsHelp,
 	}
 }
 
@@ -437,6 +1321,7 @@ func buildSliderEffectsVolume(game *Game, res *ui.UiResources, gridLayoutContain
 			text.Label = fmt.Sprintf("%d", args.Current)
 			game.settings.RawSettings.EffectsVolume = args.Current
 			game.explosionAnimation.SetVolume(float64(game.settings.RawSettings.EffectsVolume) / 100)
+			game.siren.SetVolume(float64(game.settings.RawSettings.EffectsVolume) / 100)
 		}),
 	)
 	slider.Current = game.settings.SavedSettings.EffectsVolume
@@ -468,7 +1353,7 @@ func buildSliderCarSensitivity(game *Game, res *ui.UiResources, gridLayoutContai
 		widget.SliderOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.RowLayoutData{
 			Position: widget.RowLayoutPositionStart,
 		}), widget.WidgetOpts.MinSize(200, 6)),
-		widget.SliderOpts.MinMax(50, 100),
+		widget.SliderOpts.MinMax(replay.MinSpeed*10, replay.MaxSpeed*10),
 		widget.SliderOpts.Images(res.Slider.TrackImage, res.Slider.Handle),
 		widget.SliderOpts.FixedHandleSize(res.Slider.HandleSize),
 		widget.SliderOpts.TrackOffset(5),
@@ -479,6 +1364,7 @@ func buildSliderCarSensitivity(game *Game, res *ui.UiResources, gridLayoutContai
 			text.Label = fmt.Sprintf("%d", args.Current)
 			game.settings.RawSettings.CarSensitivity = float64(args.Current) / 10
 			game.player.SetSpeed(game.settings.RawSettings.CarSensitivity)
+			game.secondPlayer.SetSpeed(game.settings.RawSettings.CarSensitivity)
 		}),
 	)
 	slider.Current = int(game.settings.SavedSettings.CarSensitivity * 10)
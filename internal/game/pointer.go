@@ -0,0 +1,86 @@
+package game
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/VxVxN/game/internal/dashboard"
+	"github.com/VxVxN/game/internal/input"
+	"github.com/VxVxN/game/internal/stager"
+	playerpkg "github.com/VxVxN/game/pkg/player"
+)
+
+// pointerDirections returns the directions the mouse or the touches steer the player in, none if the settings
+// leave the steering to the keys and the gamepads.
+func (game *Game) pointerDirections(player *playerpkg.Player) []ebiten.Key {
+	return input.PointerDirections(game.settings.SavedSettings.PointerSteering, player.X+player.Width/2,
+		int(game.windowWidth), int(game.windowHeight))
+}
+
+// steerByPointer keeps the directions of the pointer for the tick like the ones of the keys.
+func (game *Game) steerByPointer() {
+	if game.modeSteering {
+		return
+	}
+	for _, direction := range game.pointerDirections(game.player) {
+		game.steerPlayer(direction)
+	}
+}
+
+// clicked reports whether the left mouse button or a touch was just pressed and where.
+func clicked() (int, int, bool) {
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		return x, y, true
+	}
+	if touches := inpututil.AppendJustPressedTouchIDs(nil); len(touches) > 0 {
+		x, y := ebiten.TouchPosition(touches[0])
+		return x, y, true
+	}
+	return 0, 0, false
+}
+
+// updateClicks handles the clicks and the touches of the pages that aren't built with ebitenui.
+func (game *Game) updateClicks() {
+	x, y, ok := clicked()
+	if !ok {
+		return
+	}
+	switch game.stager.Stage() {
+	case stager.GameOverStage:
+		game.restart()
+	case stager.DashboardStage:
+		switch game.dashboard.Click(x, y) {
+		case dashboard.RatingsCommand:
+			game.stager.SetStage(stager.StatisticsStage)
+		case dashboard.ExitCommand:
+			game.stager.SetStage(stager.MainMenuStage)
+		}
+	}
+}
+
+// drawTouchZones shows where the screen has to be touched to steer.
+func (game *Game) drawTouchZones(screen *ebiten.Image) {
+	if game.settings.SavedSettings.PointerSteering != input.PointerTouch {
+		return
+	}
+	textFace := &text.GoTextFace{
+		Source: game.textFaceSource,
+		Size:   24,
+	}
+	for _, zone := range input.TouchZones(int(game.windowWidth), int(game.windowHeight)) {
+		bounds := zone.Bounds
+		vector.StrokeRect(screen, float32(bounds.Min.X), float32(bounds.Min.Y), float32(bounds.Dx()), float32(bounds.Dy()), 2,
+			color.RGBA{R: 255, G: 255, B: 255, A: 40}, false)
+
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(float64(bounds.Min.X+bounds.Dx()/2), float64(bounds.Max.Y-60))
+		op.ColorScale.ScaleAlpha(0.4)
+		op.LayoutOptions.PrimaryAlign = text.AlignCenter
+		text.Draw(screen, zone.Name, textFace, op)
+	}
+}
@@ -0,0 +1,140 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/VxVxN/game/internal/datadir"
+	"github.com/VxVxN/game/internal/profile"
+	"github.com/VxVxN/game/internal/stager"
+	"github.com/VxVxN/game/pkg/statisticer"
+)
+
+const defaultProfileName = "Player"
+
+// loadProfiles opens the profiles. The first time the records that were kept before there were profiles
+// become the records of the default profile, which has the campaign progress already.
+func loadProfiles(dataDir datadir.Dir, logger *slog.Logger) (*profile.Profiles, error) {
+	profiles, err := profile.Load(dataDir.Path("profiles.json"), dataDir.Path("profiles"))
+	if errors.Is(err, profile.ErrCorrupt) {
+		logger.Error("Recovered the profiles from a corrupt file", "error", err)
+	} else if err != nil {
+		return nil, err
+	}
+	if len(profiles.Profiles) > 0 {
+		return profiles, nil
+	}
+	defaultProfile, err := profiles.CreateWithID(profile.DefaultID, defaultProfileName)
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(dataDir.Path("statistics*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if err = os.Rename(file, filepath.Join(profiles.Dir(defaultProfile), filepath.Base(file))); err != nil {
+			return nil, fmt.Errorf("failed to move records to the default profile: %v", err)
+		}
+	}
+	profiles.Active = defaultProfile.ID
+	if err = profiles.Save(); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// statisticersOf returns the records of the profile for every mode that is ranked.
+func (game *Game) statisticersOf(profile *profile.Profile) map[string]*statisticer.Statisticer {
+	return statisticersIn(game.profiles.Dir(profile), game.modes)
+}
+
+// statisticersIn returns the records kept in the directory of a profile for every mode that is ranked.
+func statisticersIn(dir string, modes []Mode) map[string]*statisticer.Statisticer {
+	statisticers := make(map[string]*statisticer.Statisticer, len(modes))
+	for _, mode := range modes {
+		if _, ok := mode.(*versusMode); ok {
+			continue // two scores in one run, there is nothing to rank
+		}
+		fileName := "statistics_" + mode.ID() + ".json"
+		if mode.ID() == "endless" {
+			fileName = "statistics.json" // records made before there were modes
+		}
+		statisticers[mode.ID()] = statisticer.NewStatisticer(filepath.Join(dir, fileName))
+	}
+	return statisticers
+}
+
+// historyOf loads the runs of the profile for the dashboard. The records of the profile that is played
+// can be repaired on the recovery page, the ones of the others are left out until their profile is played.
+func (game *Game) historyOf(player *profile.Profile, board *statisticer.Statisticer) ([]statisticer.Record, bool) {
+	if player == game.profile {
+		return game.loadRecords(board, func() {
+			game.stager.SetStage(stager.DashboardStage)
+		})
+	}
+	records, err := board.Load()
+	if err != nil {
+		game.logger.Error("Failed to load statistics", "path", board.Path(), "error", err)
+	}
+	return records, true
+}
+
+// useProfile makes the profile the one that is played, its runs are saved under it from now on.
+func (game *Game) useProfile(profile *profile.Profile) {
+	game.profile = profile
+	game.profiles.Active = profile.ID
+	game.statisticers = game.statisticersOf(profile)
+	game.loadAchievements(profile)
+	game.player.SetName(profile.Name)
+	game.settings.UseOverrides(&profile.Settings)
+	game.ApplySettings()
+	game.saveProfiles()
+}
+
+func (game *Game) saveProfiles() {
+	if err := game.profiles.Save(); err != nil {
+		game.logger.Error("Failed to save profiles", "error", err)
+	}
+}
+
+// nameProfile creates a profile with the name, or renames the one that is being renamed.
+func (game *Game) nameProfile(name string) error {
+	if game.renaming != nil {
+		if err := game.profiles.Rename(game.renaming, name); err != nil {
+			return err
+		}
+		if game.renaming == game.profile {
+			game.player.SetName(game.profile.Name)
+		}
+		game.saveProfiles()
+		game.stager.SetStage(stager.ProfilesStage)
+		return nil
+	}
+	created, err := game.profiles.Create(name)
+	if err != nil {
+		return err
+	}
+	game.useProfile(created)
+	game.stager.SetStage(stager.MainMenuStage)
+	return nil
+}
+
+// deleteProfile removes the profile with its records and campaign progress.
+func (game *Game) deleteProfile(deleted *profile.Profile) error {
+	if err := game.profiles.Delete(deleted); err != nil {
+		return err
+	}
+	game.unlocks.Forget(deleted.ID)
+	if err := game.unlocks.Save(); err != nil {
+		game.logger.Error("Failed to save campaign progress", "error", err)
+	}
+	if deleted == game.profile {
+		game.useProfile(game.profiles.Current())
+	}
+	game.saveProfiles()
+	return nil
+}
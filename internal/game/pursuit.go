@@ -0,0 +1,131 @@
+package game
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	escapeBonus     = 100
+	pursuitCooldown = 600 // ticks until the next police car shows up after an escape
+	lightRadius     = 90
+)
+
+type pursuitMode struct {
+	cooldown int
+}
+
+func (mode *pursuitMode) ID() string {
+	return "pursuit"
+}
+
+func (mode *pursuitMode) Name() string {
+	return "Police pursuit"
+}
+
+func (mode *pursuitMode) Setup(game *Game) {
+	mode.startChase(game)
+}
+
+func (mode *pursuitMode) startChase(game *Game) {
+	mode.cooldown = 0
+	game.chaser.Reset(game.player.Rectangle)
+	game.chaser.SetSunDirection(game.sunDirection)
+	if err := game.siren.Rewind(); err != nil {
+		game.logger.Error("Failed to rewind siren", "error", err)
+	}
+	game.siren.Play()
+}
+
+// Update runs the police car and reports whether it has caught the player.
+func (mode *pursuitMode) Update(game *Game) bool {
+	if mode.cooldown > 0 {
+		mode.cooldown--
+		if mode.cooldown == 0 {
+			mode.startChase(game)
+		}
+		return false
+	}
+
+	game.chaser.Update(game.player.Rectangle, game.cars)
+	if game.chaser.Caught(game.player.Rectangle) {
+		game.siren.Pause()
+		game.crash(game.player, "police")
+		return true
+	}
+	if game.chaser.Escaped() {
+		game.logger.Debug("Pursuit cleared")
+		game.siren.Pause()
+		game.player.AddPoints(escapeBonus)
+		mode.cooldown = pursuitCooldown
+	}
+	return false
+}
+
+// Collision lets traffic only slow the player down, which lets the police catch up.
+func (mode *pursuitMode) Collision(game *Game, cause string) bool {
+	if cause != "car" {
+		return true
+	}
+	if game.cars.Hit(game.player.Rectangle) {
+		game.player.Wobble()
+		game.chaser.Boost()
+	}
+	return false
+}
+
+func (mode *pursuitMode) Points(game *Game) float64 {
+	return pointsPerTick
+}
+
+func (mode *pursuitMode) Finished(game *Game) bool {
+	return false
+}
+
+func (mode *pursuitMode) DrawHUD(game *Game, screen *ebiten.Image, textFace text.Face) {
+	const width, height = 200, 20
+	x, y := float32(20), float32(20)
+
+	drawHUDText(screen, "Heat", textFace, float64(x), float64(y))
+
+	y += 32
+	heat := float32(0)
+	if mode.cooldown == 0 {
+		heat = float32(game.chaser.Heat())
+	}
+	vector.DrawFilledRect(screen, x, y, width, height, color.RGBA{R: 40, G: 40, B: 40, A: 200}, false)
+	vector.DrawFilledRect(screen, x, y, width*heat, height, color.RGBA{R: 255, G: uint8(200 * (1 - heat)), A: 255}, false)
+	vector.StrokeRect(screen, x, y, width, height, 2, color.Black, false)
+}
+
+func (game *Game) chaserVisible() bool {
+	mode, ok := game.mode.(*pursuitMode)
+	return ok && mode.cooldown == 0
+}
+
+// cutPoliceLights lets the flashing lights shine through the darkness.
+func (game *Game) cutPoliceLights() {
+	x, y, _ := game.chaser.Lights()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(x-lightRadius, y-lightRadius)
+	op.Blend = ebiten.BlendDestinationOut
+	game.nightImage.DrawImage(game.lightImage, op)
+}
+
+func (game *Game) drawPoliceGlow(screen *ebiten.Image) {
+	x, y, red := game.chaser.Lights()
+	glow := color.NRGBA{R: 30, G: 120, B: 255, A: 70}
+	if red {
+		glow = color.NRGBA{R: 255, G: 30, B: 30, A: 70}
+	}
+	vector.DrawFilledCircle(screen, float32(x), float32(y), lightRadius, glow, true)
+}
+
+func newLightImage() *ebiten.Image {
+	image := ebiten.NewImage(lightRadius*2, lightRadius*2)
+	vector.DrawFilledCircle(image, lightRadius, lightRadius, lightRadius, color.White, true)
+	return image
+}
@@ -0,0 +1,168 @@
+package game
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/VxVxN/game/internal/datadir"
+	"github.com/VxVxN/game/internal/garage"
+	"github.com/VxVxN/game/pkg/replay"
+)
+
+// lastReplayFile keeps the replay of the last finished run, e.g. to check it with racer verify.
+const lastReplayFile = "last_replay.json"
+
+var replayInputs = map[ebiten.Key]replay.Input{
+	ebiten.KeyLeft:  replay.InputLeft,
+	ebiten.KeyRight: replay.InputRight,
+	ebiten.KeyUp:    replay.InputUp,
+	ebiten.KeyDown:  replay.InputDown,
+}
+
+// steeringOrder is the order the directions of a tick steer the player in, live and in a replay.
+var steeringOrder = []ebiten.Key{ebiten.KeyLeft, ebiten.KeyRight, ebiten.KeyUp, ebiten.KeyDown}
+
+// steerPlayer keeps the direction for the replay of the tick, the player is steered by all of them
+// at once before the step, whichever device they came from.
+func (game *Game) steerPlayer(direction ebiten.Key) {
+	game.tickInput |= replayInputs[direction]
+}
+
+// steerByInput steers the player with the directions of the tick.
+func (game *Game) steerByInput() {
+	for _, direction := range steeringOrder {
+		if game.tickInput&replayInputs[direction] != 0 {
+			game.steer(game.player, direction)
+		}
+	}
+}
+
+// startReplay begins recording the run once the mode has set it up.
+func (game *Game) startReplay() {
+	game.tickInput = 0
+	game.replay = &replay.Replay{
+		Version:      Version,
+		Mode:         game.mode.ID(),
+		Seed:         game.seed,
+		Speed:        game.settings.RawSettings.CarSensitivity,
+		Vehicle:      game.vehicle.ID,
+		Upgrades:     game.upgrades,
+		ScreenWidth:  game.screenWidth,
+		ScreenHeight: game.screenHeight,
+		Width:        game.windowWidth,
+		Height:       game.windowHeight,
+	}
+}
+
+func (game *Game) saveReplay() {
+	game.replay.Points = int(game.player.Points())
+	if err := game.replay.Save(game.dataDir.CachePath(lastReplayFile)); err != nil {
+		game.logger.Error("Failed to save the replay", "error", err)
+	}
+}
+
+// replayMode returns the mode the replay was driven in, only the runs of ranked modes are replayed.
+func (game *Game) replayMode(run *replay.Replay) (Mode, error) {
+	if run.Vehicle != "" && garage.Find(game.vehicles, run.Vehicle) == nil {
+		return nil, fmt.Errorf("vehicle %q isn't in the garage", run.Vehicle)
+	}
+	for _, mode := range game.modes {
+		if mode.ID() == run.Mode && game.statisticers[mode.ID()] != nil {
+			return mode, nil
+		}
+	}
+	return nil, fmt.Errorf("mode %q has no leaderboard", run.Mode)
+}
+
+// watch shows the run of the replay from the start, nothing of it is kept. The window has to have
+// the size the run was driven in.
+func (game *Game) watch(run *replay.Replay) error {
+	mode, err := game.replayMode(run)
+	if err != nil {
+		return err
+	}
+	game.verifying = run
+	game.watched = run.Expand()
+	game.StartMode(mode)
+	return nil
+}
+
+// steerByReplay takes the input of the tick from the replay that is watched, it reports false once the
+// replay is over.
+func (game *Game) steerByReplay() bool {
+	if game.watchTick >= len(game.watched) {
+		return false
+	}
+	game.tickInput = game.watched[game.watchTick]
+	game.watchTick++
+	return true
+}
+
+// stopWatching lets the runs be driven by the player again.
+func (game *Game) stopWatching() {
+	game.verifying = nil
+	game.watched = nil
+}
+
+// Verify drives the run of the replay again without a window and returns the points it ends with.
+// It fails if the run doesn't end exactly where the replay does or with other points.
+func Verify(run *replay.Replay) (int, error) {
+	if err := run.Validate(); err != nil {
+		return 0, fmt.Errorf("invalid replay: %v", err)
+	}
+	// the run isn't kept with the records of anyone, the game has a data directory of its own
+	dir, err := os.MkdirTemp("", "racer-verify-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create data directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	dataDir, err := datadir.New(dir)
+	if err != nil {
+		return 0, err
+	}
+	if err = dataDir.Create(); err != nil {
+		return 0, err
+	}
+	game, err := newGame(run.ScreenWidth, run.ScreenHeight, dataDir, slog.LevelDebug)
+	if err != nil {
+		return 0, fmt.Errorf("failed to init game: %v", err)
+	}
+	defer game.Close()
+
+	mode, err := game.replayMode(run)
+	if err != nil {
+		return 0, err
+	}
+
+	game.audioPlayer.SetVolume(0)
+	game.explosionAnimation.SetVolume(0)
+	game.siren.SetVolume(0)
+	game.windowWidth, game.windowHeight = run.Width, run.Height
+	game.settings.RawSettings.CarSensitivity = run.Speed
+	game.verifying = run
+	game.StartMode(mode)
+
+	inputs := run.Expand()
+	var ticks int
+	for !game.player.Dead() && ticks < len(inputs) {
+		game.tickInput = inputs[ticks]
+		game.steerByInput()
+		ticks++
+		if game.step() {
+			break
+		}
+	}
+	points := int(game.player.Points())
+	switch {
+	case !game.player.Dead():
+		return points, fmt.Errorf("the replay ends after %d ticks while the run goes on", ticks)
+	case ticks != len(inputs):
+		return points, fmt.Errorf("the run ends after %d ticks, the replay has %d", ticks, len(inputs))
+	case points != run.Points:
+		return points, fmt.Errorf("the run ends with %d points, the replay claims %d", points, run.Points)
+	}
+	return points, nil
+}
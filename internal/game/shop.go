@@ -0,0 +1,54 @@
+package game
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/VxVxN/game/internal/garage"
+	"github.com/VxVxN/game/internal/shop"
+)
+
+// payRun pays the coins of the finished run to the profile.
+func (game *Game) payRun() {
+	if game.verifying != nil {
+		return
+	}
+	game.run.coins = shop.Coins(game.background.Distance()-game.startDistance, game.nearMisses)
+	game.profile.Purchases.Earn(game.run.coins)
+	game.saveProfiles()
+}
+
+// buy runs the purchase and keeps it in the profile.
+func (game *Game) buy(purchase func(purchases *shop.Purchases) error) error {
+	if err := purchase(&game.profile.Purchases); err != nil {
+		return err
+	}
+	game.saveProfiles()
+	return nil
+}
+
+func (game *Game) buyVehicle(vehicle *garage.Vehicle) error {
+	return game.buy(func(purchases *shop.Purchases) error {
+		return purchases.BuyVehicle(vehicle.ID, vehicle.Price)
+	})
+}
+
+// paint gives the cars of the profile the paint, it is bought first if it isn't yet.
+func (game *Game) paint(paint shop.Paint) error {
+	if !game.profile.Purchases.OwnsPaint(paint) {
+		if err := game.buy(func(purchases *shop.Purchases) error {
+			return purchases.BuyPaint(paint)
+		}); err != nil {
+			return err
+		}
+	}
+	game.profile.Paint = paint.ID
+	game.saveProfiles()
+	return nil
+}
+
+func paintTint(id string) ebiten.ColorScale {
+	paint := shop.FindPaint(id)
+	var tint ebiten.ColorScale
+	tint.Scale(paint.Tint[0], paint.Tint[1], paint.Tint[2], 1)
+	return tint
+}
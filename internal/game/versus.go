@@ -0,0 +1,161 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+
+	"github.com/VxVxN/game/internal/garage"
+	"github.com/VxVxN/game/internal/input"
+	playerpkg "github.com/VxVxN/game/pkg/player"
+)
+
+const bumpDistance = 25 // pixels the cars bounce apart after touching
+
+// controls are the keys of one player and the gamepad of which player it uses, the keys are nil
+// for the player who uses the bindings of the keyboard and the mouse.
+type controls struct {
+	keys    map[input.Action]ebiten.Key
+	gamepad int
+}
+
+var (
+	playerOneControls = controls{gamepad: 0}
+	playerTwoControls = controls{keys: map[input.Action]ebiten.Key{
+		input.SteerLeft: ebiten.KeyA, input.SteerRight: ebiten.KeyD, input.Throttle: ebiten.KeyW, input.Brake: ebiten.KeyS,
+	}, gamepad: 1}
+)
+
+// heldDirections returns the held steering actions of the player as the arrow keys Player.Move understands.
+// The player at the keyboard can steer with the mouse or by touch as well.
+func (game *Game) heldDirections(controls controls, player *playerpkg.Player) []ebiten.Key {
+	var pointer []ebiten.Key
+	if controls.keys == nil {
+		pointer = game.pointerDirections(player)
+	}
+	var directions []ebiten.Key
+	for _, steering := range []struct {
+		action    input.Action
+		direction ebiten.Key
+	}{
+		{input.SteerLeft, ebiten.KeyLeft},
+		{input.SteerRight, ebiten.KeyRight},
+		{input.Throttle, ebiten.KeyUp},
+		{input.Brake, ebiten.KeyDown},
+	} {
+		held := game.eventManager.HeldWithKeyboardOrMouse(steering.action) || slices.Contains(pointer, steering.direction)
+		if controls.keys != nil {
+			held = ebiten.IsKeyPressed(controls.keys[steering.action])
+		}
+		if id, ok := game.gamepads.Gamepad(controls.gamepad); ok && game.eventManager.HeldWithGamepad(steering.action, id) {
+			held = true
+		}
+		if held {
+			directions = append(directions, steering.direction)
+		}
+	}
+	return directions
+}
+
+// versusMode puts two players on the road, the first one to crash loses.
+type versusMode struct {
+	result string
+}
+
+func (mode *versusMode) ID() string {
+	return "versus"
+}
+
+func (mode *versusMode) Name() string {
+	return "Versus"
+}
+
+func (mode *versusMode) Setup(game *Game) {
+	mode.result = ""
+	game.twoPlayers = true
+	game.modeSteering = true
+	// both players race the same car, what the profile has bought would give the first one an edge
+	for _, player := range []*playerpkg.Player{game.player, game.secondPlayer} {
+		game.drive(player, garage.Find(game.vehicles, garage.DefaultID), nil)
+		player.SetSpeed(game.settings.RawSettings.CarSensitivity)
+	}
+	game.player.SetTint(paintTint(""))
+	game.player.SetPosition(game.windowWidth/2-250, game.windowHeight/2)
+	game.secondPlayer.SetPosition(game.windowWidth/2+150, game.windowHeight/2)
+}
+
+// Update steers both cars, since the event manager handles one key per tick, and runs everything
+// the second player can run into.
+func (mode *versusMode) Update(game *Game) bool {
+	for _, direction := range game.heldDirections(playerOneControls, game.player) {
+		game.steer(game.player, direction)
+	}
+	for _, direction := range game.heldDirections(playerTwoControls, game.secondPlayer) {
+		game.steer(game.secondPlayer, direction)
+	}
+	bump(game.player, game.secondPlayer)
+
+	rival := game.secondPlayer
+	if cause, crashed := game.collideOpponent(rival); crashed {
+		mode.result = "Player 1 wins!"
+		game.crash(rival, cause)
+		return true
+	}
+	rival.Update()
+	rival.AddPoints(mode.Points(game))
+	game.clampPlayer(rival)
+	return false
+}
+
+// Collision is a crash of the first player, which hands the win to the second one.
+func (mode *versusMode) Collision(game *Game, cause string) bool {
+	mode.result = "Player 2 wins!"
+	return true
+}
+
+func (mode *versusMode) Points(game *Game) float64 {
+	return pointsPerTick
+}
+
+func (mode *versusMode) Finished(game *Game) bool {
+	return false
+}
+
+func (mode *versusMode) DrawHUD(game *Game, screen *ebiten.Image, textFace text.Face) {
+	drawHUDText(screen, fmt.Sprintf("Player 1 (arrows): %d", int(game.player.Points())), textFace, 20, 20)
+	drawHUDText(screen, fmt.Sprintf("Player 2 (WASD): %d", int(game.secondPlayer.Points())), textFace, 20, 50)
+	if mode.result != "" {
+		drawHUDText(screen, mode.result, textFace, 20, 90)
+	}
+}
+
+// bump pushes touching cars apart along the side they touch on and shakes both of them.
+func bump(first, second *playerpkg.Player) {
+	if !first.Collision(second.Rectangle) {
+		return
+	}
+	dx := (first.X + first.Width/2) - (second.X + second.Width/2)
+	dy := (first.Y + first.Height/2) - (second.Y + second.Height/2)
+	overlapX := (first.Width+second.Width)/2 - math.Abs(dx)
+	overlapY := (first.Height+second.Height)/2 - math.Abs(dy)
+	if overlapX < overlapY {
+		push := overlapX/2 + bumpDistance
+		if dx < 0 {
+			push = -push
+		}
+		first.X += push
+		second.X -= push
+	} else {
+		push := overlapY/2 + bumpDistance
+		if dy < 0 {
+			push = -push
+		}
+		first.Y += push
+		second.Y -= push
+	}
+	first.Wobble()
+	second.Wobble()
+}
@@ -0,0 +1,114 @@
+package garage
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/VxVxN/game/pkg/player"
+)
+
+// DefaultID is the car that was driven before there was a garage.
+const DefaultID = "racer"
+
+// Sprite is a part of an image in the assets.
+type Sprite struct {
+	Sheet string
+	Rect  [4]int // min x, min y, max x, max y
+}
+
+func (sprite Sprite) Bounds() image.Rectangle {
+	return image.Rect(sprite.Rect[0], sprite.Rect[1], sprite.Rect[2], sprite.Rect[3])
+}
+
+// Vehicle is a car the player can drive.
+type Vehicle struct {
+	ID          string `json:"-"`
+	Name        string
+	Description string
+	Sprite      Sprite
+	Shadow      Sprite
+	// Hitbox is where the vehicle can be hit, relative to the sprite. The whole sprite if it is empty.
+	Hitbox   [4]int `json:",omitempty"`
+	Handling player.Handling
+	Price    int `json:",omitempty"` // coins it costs in the shop, every profile has the ones without a price
+}
+
+// HitboxBounds returns the hitbox relative to the top left corner of the sprite.
+func (vehicle *Vehicle) HitboxBounds() image.Rectangle {
+	if vehicle.Hitbox == [4]int{} {
+		bounds := vehicle.Sprite.Bounds()
+		return bounds.Sub(bounds.Min)
+	}
+	return image.Rect(vehicle.Hitbox[0], vehicle.Hitbox[1], vehicle.Hitbox[2], vehicle.Hitbox[3])
+}
+
+// Load reads every vehicle in dir, they are in the order of their file names.
+func Load(dir string) ([]*Vehicle, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vehicles directory: %v", err)
+	}
+	sort.Strings(names)
+
+	vehicles := make([]*Vehicle, 0, len(names))
+	for _, name := range names {
+		vehicle, err := LoadVehicle(name)
+		if err != nil {
+			return nil, err
+		}
+		vehicles = append(vehicles, vehicle)
+	}
+	if vehicle := Find(vehicles, DefaultID); vehicle == nil || vehicle.Price != 0 {
+		return nil, fmt.Errorf("there is no free vehicle %s in %s", DefaultID, dir)
+	}
+	return vehicles, nil
+}
+
+func LoadVehicle(path string) (*Vehicle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vehicle: %v", err)
+	}
+	vehicle := &Vehicle{}
+	if err = json.Unmarshal(data, vehicle); err != nil {
+		return nil, fmt.Errorf("failed to parse vehicle %s: %v", path, err)
+	}
+	vehicle.ID = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if err = vehicle.validate(); err != nil {
+		return nil, fmt.Errorf("vehicle %s: %v", path, err)
+	}
+	return vehicle, nil
+}
+
+func (vehicle *Vehicle) validate() error {
+	sprite := vehicle.Sprite.Bounds()
+	handling := vehicle.Handling
+	switch {
+	case vehicle.Sprite.Sheet == "" || sprite.Empty():
+		return fmt.Errorf("the sprite is missing")
+	case vehicle.Shadow.Sheet == "" || vehicle.Shadow.Bounds().Empty():
+		return fmt.Errorf("the shadow is missing")
+	case vehicle.HitboxBounds().Empty() || !vehicle.HitboxBounds().In(sprite.Sub(sprite.Min)):
+		return fmt.Errorf("the hitbox is outside of the sprite")
+	case handling.Steering <= 0 || handling.Acceleration <= 0 || handling.TopSpeed <= 0 || handling.Brakes <= 0:
+		return fmt.Errorf("steering, acceleration, top speed and brakes must be positive")
+	case handling.Armour < 0 || vehicle.Price < 0:
+		return fmt.Errorf("armour and price can't be negative")
+	}
+	return nil
+}
+
+// Find returns the vehicle with the ID, nil if there is none.
+func Find(vehicles []*Vehicle, id string) *Vehicle {
+	for _, vehicle := range vehicles {
+		if vehicle.ID == id {
+			return vehicle
+		}
+	}
+	return nil
+}
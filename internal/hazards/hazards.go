@@ -0,0 +1,239 @@
+package hazards
+
+import (
+	"image/color"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/VxVxN/gamedevlib/rectangle"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	lanes           = 5
+	laneWidth       = 200
+	firstLaneCenter = 120
+	spawnY          = -200
+)
+
+type Kind int
+
+const (
+	Cone Kind = iota
+	Barrier
+	Pothole
+	OilSlick
+	Debris
+)
+
+func (kind Kind) String() string {
+	switch kind {
+	case Cone:
+		return "Cone"
+	case Barrier:
+		return "Barrier"
+	case Pothole:
+		return "Pothole"
+	case OilSlick:
+		return "OilSlick"
+	case Debris:
+		return "Debris"
+	}
+	return ""
+}
+
+// Lethal hazards end the run, the others only upset the handling of the car.
+func (kind Kind) Lethal() bool {
+	return kind == Cone || kind == Barrier
+}
+
+type Hazard struct {
+	*rectangle.Rectangle
+	kind Kind
+	lane int
+}
+
+func (hazard *Hazard) Kind() Kind {
+	return hazard.kind
+}
+
+type Spawner struct {
+	screenHeight float64
+	startRoad    float64
+	barrierImage *ebiten.Image
+	potholeImage *ebiten.Image
+	hazards      []*Hazard
+	rand         *rand.Rand
+	nextSpawn    float64
+	enabled      bool
+}
+
+func New(barrierImage, potholeImage *ebiten.Image, screenHeight, startRoad float64) *Spawner {
+	return &Spawner{
+		screenHeight: screenHeight,
+		startRoad:    startRoad,
+		barrierImage: barrierImage,
+		potholeImage: potholeImage,
+		rand:         rand.New(rand.NewPCG(0, 0)),
+		enabled:      true,
+	}
+}
+
+// SetEnabled turns random spawning on or off, hazards already on the road stay.
+func (spawner *Spawner) SetEnabled(enabled bool) {
+	spawner.enabled = enabled
+}
+
+func (spawner *Spawner) Reset(seed uint64) {
+	spawner.rand = rand.New(rand.NewPCG(seed, seed^0x4a2a4d))
+	spawner.hazards = spawner.hazards[:0]
+	spawner.nextSpawn = spawner.screenHeight
+}
+
+// Update scrolls hazards with the road. emptyLanes are the lanes the traffic keeps passable,
+// lethal hazards are only placed so that at least one of them stays open.
+func (spawner *Spawner) Update(scrollSpeed float64, emptyLanes [lanes]bool) {
+	hazards := spawner.hazards[:0]
+	for _, hazard := range spawner.hazards {
+		hazard.Y += scrollSpeed
+		if hazard.Y > spawner.screenHeight {
+			continue
+		}
+		hazards = append(hazards, hazard)
+	}
+	spawner.hazards = hazards
+
+	if !spawner.enabled {
+		return
+	}
+	spawner.nextSpawn -= scrollSpeed
+	if spawner.nextSpawn > 0 {
+		return
+	}
+	spawner.nextSpawn = 400 + spawner.rand.Float64()*800
+	spawner.spawn(emptyLanes)
+}
+
+func (spawner *Spawner) spawn(emptyLanes [lanes]bool) {
+	kind := Kind(spawner.rand.IntN(int(Debris) + 1))
+	lane := spawner.rand.IntN(lanes)
+	if kind.Lethal() {
+		blocked := spawner.BlockedLanes()
+		blocked[lane] = true
+		var passable bool
+		for i := range emptyLanes {
+			if emptyLanes[i] && !blocked[i] {
+				passable = true
+				break
+			}
+		}
+		if !passable {
+			kind = Pothole
+		}
+	}
+
+	spawner.Spawn(kind, lane, spawnY)
+}
+
+// Spawn places a hazard into a lane at the given screen height.
+func (spawner *Spawner) Spawn(kind Kind, lane int, y float64) {
+	width, height := kind.size()
+	spawner.hazards = append(spawner.hazards, &Hazard{
+		Rectangle: rectangle.New(spawner.startRoad+firstLaneCenter+float64(lane*laneWidth)-width/2, y, width, height),
+		kind:      kind,
+		lane:      lane,
+	})
+}
+
+func (kind Kind) size() (float64, float64) {
+	switch kind {
+	case Cone:
+		return 40, 40
+	case Barrier:
+		return 150, 30
+	case Pothole:
+		return 70, 70
+	case OilSlick:
+		return 110, 80
+	case Debris:
+		return 80, 50
+	}
+	return 0, 0
+}
+
+// BlockedLanes reports lanes closed by a lethal hazard that is still on the road.
+func (spawner *Spawner) BlockedLanes() [lanes]bool {
+	var blocked [lanes]bool
+	for _, hazard := range spawner.hazards {
+		if hazard.kind.Lethal() {
+			blocked[hazard.lane] = true
+		}
+	}
+	return blocked
+}
+
+// Passable reports whether a lethal hazard placed into the lane would leave another lane open.
+func (spawner *Spawner) Passable(lane int) bool {
+	blocked := spawner.BlockedLanes()
+	blocked[lane] = true
+	return slices.Contains(blocked[:], false)
+}
+
+// Collision returns the hazard hit by the rectangle. Non-lethal hazards are removed once hit,
+// so their effect triggers only once.
+func (spawner *Spawner) Collision(rectangle *rectangle.Rectangle) (*Hazard, bool) {
+	for i, hazard := range spawner.hazards {
+		if !hazard.Collision(rectangle) {
+			continue
+		}
+		if !hazard.kind.Lethal() {
+			spawner.hazards = append(spawner.hazards[:i], spawner.hazards[i+1:]...)
+		}
+		return hazard, true
+	}
+	return nil, false
+}
+
+func (spawner *Spawner) Draw(screen *ebiten.Image) {
+	for _, hazard := range spawner.hazards {
+		spawner.drawHazard(screen, hazard)
+	}
+}
+
+func (spawner *Spawner) drawHazard(screen *ebiten.Image, hazard *Hazard) {
+	x, y := float32(hazard.X), float32(hazard.Y)
+	w, h := float32(hazard.Width), float32(hazard.Height)
+	switch hazard.kind {
+	case Cone:
+		vector.DrawFilledCircle(screen, x+w/2, y+h/2, w/2, color.RGBA{R: 245, G: 120, B: 20, A: 255}, true)
+		vector.StrokeCircle(screen, x+w/2, y+h/2, w/3, 4, color.White, true)
+		vector.DrawFilledCircle(screen, x+w/2, y+h/2, w/8, color.RGBA{R: 200, G: 80, B: 10, A: 255}, true)
+	case Barrier:
+		// the barrier sprite stands upright in the sheet, lay it across the lane
+		op := &ebiten.DrawImageOptions{}
+		bounds := spawner.barrierImage.Bounds()
+		op.GeoM.Rotate(-math.Pi / 2)
+		op.GeoM.Scale(hazard.Width/float64(bounds.Dy()), hazard.Height/float64(bounds.Dx()))
+		op.GeoM.Translate(hazard.X, hazard.Y+hazard.Height)
+		screen.DrawImage(spawner.barrierImage, op)
+	case Pothole:
+		op := &ebiten.DrawImageOptions{}
+		bounds := spawner.potholeImage.Bounds()
+		op.GeoM.Scale(hazard.Width/float64(bounds.Dx()), hazard.Height/float64(bounds.Dy()))
+		op.GeoM.Translate(hazard.X, hazard.Y)
+		screen.DrawImage(spawner.potholeImage, op)
+	case OilSlick:
+		oil := color.RGBA{R: 20, G: 20, B: 30, A: 220}
+		vector.DrawFilledCircle(screen, x+w*0.35, y+h*0.5, h*0.4, oil, true)
+		vector.DrawFilledCircle(screen, x+w*0.65, y+h*0.45, h*0.35, oil, true)
+		vector.DrawFilledCircle(screen, x+w*0.5, y+h*0.7, h*0.25, oil, true)
+		vector.DrawFilledCircle(screen, x+w*0.4, y+h*0.35, h*0.1, color.RGBA{R: 90, G: 70, B: 140, A: 160}, true)
+	case Debris:
+		gray := color.RGBA{R: 100, G: 100, B: 100, A: 255}
+		vector.DrawFilledRect(screen, x, y+h*0.2, w*0.4, h*0.3, gray, false)
+		vector.DrawFilledRect(screen, x+w*0.5, y, w*0.3, h*0.4, color.RGBA{R: 140, G: 120, B: 90, A: 255}, false)
+		vector.DrawFilledRect(screen, x+w*0.3, y+h*0.6, w*0.6, h*0.3, gray, false)
+	}
+}
@@ -0,0 +1,98 @@
+package input
+
+import "fmt"
+
+// Action is something the player does, the keyboard, the gamepads and the mouse are bound to actions.
+type Action int
+
+const (
+	SteerLeft Action = iota
+	SteerRight
+	Throttle
+	Brake
+	Pause
+	MenuUp
+	MenuDown
+	MenuLeft
+	MenuRight
+	Confirm
+	Back
+	SwitchView // between the ratings and the charts
+	PreviousTrack
+	NextTrack
+	actionCount
+)
+
+// context is where an action is used, two actions can share a binding if they are used in different places.
+type context int
+
+const (
+	driving context = iota
+	menus
+	everywhere
+)
+
+var actionInfo = [actionCount]struct {
+	name    string // in settings.json
+	title   string
+	context context
+}{
+	SteerLeft:     {"SteerLeft", "Steer left", driving},
+	SteerRight:    {"SteerRight", "Steer right", driving},
+	Throttle:      {"Throttle", "Throttle", driving},
+	Brake:         {"Brake", "Brake", driving},
+	Pause:         {"Pause", "Pause", driving},
+	MenuUp:        {"MenuUp", "Menu up", menus},
+	MenuDown:      {"MenuDown", "Menu down", menus},
+	MenuLeft:      {"MenuLeft", "Menu left", menus},
+	MenuRight:     {"MenuRight", "Menu right", menus},
+	Confirm:       {"Confirm", "Confirm", menus},
+	Back:          {"Back", "Back", menus},
+	SwitchView:    {"SwitchView", "Ratings and charts", menus},
+	PreviousTrack: {"PreviousTrack", "Previous song", everywhere},
+	NextTrack:     {"NextTrack", "Next song", everywhere},
+}
+
+// Actions returns every action in the order they are shown.
+func Actions() []Action {
+	actions := make([]Action, actionCount)
+	for i := range actions {
+		actions[i] = Action(i)
+	}
+	return actions
+}
+
+func (action Action) String() string {
+	if action < 0 || action >= actionCount {
+		return fmt.Sprintf("Action(%d)", int(action))
+	}
+	return actionInfo[action].name
+}
+
+// Title returns the name of the action for the controls page.
+func (action Action) Title() string {
+	return actionInfo[action].title
+}
+
+// shares reports whether the actions are used in the same place, so they can't have the same binding.
+func (action Action) shares(other Action) bool {
+	first, second := actionInfo[action].context, actionInfo[other].context
+	return first == second || first == everywhere || second == everywhere
+}
+
+func (action Action) MarshalText() ([]byte, error) {
+	if action < 0 || action >= actionCount {
+		return nil, fmt.Errorf("unknown action %d", int(action))
+	}
+	return []byte(action.String()), nil
+}
+
+func (action *Action) UnmarshalText(text []byte) error {
+	for i, info := range actionInfo {
+		if info.name == string(text) {
+			*action = Action(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown action %q", text)
+}
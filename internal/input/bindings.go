@@ -0,0 +1,247 @@
+package input
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type Device int
+
+const (
+	Keyboard Device = iota
+	Gamepad
+	Mouse
+)
+
+var devicePrefixes = map[Device]string{
+	Keyboard: "key",
+	Gamepad:  "pad",
+	Mouse:    "mouse",
+}
+
+// GamepadInput is a button of a gamepad with the standard layout or a direction of its left stick.
+type GamepadInput int
+
+const (
+	LeftStickLeft GamepadInput = GamepadInput(ebiten.StandardGamepadButtonMax) + 1 + iota
+	LeftStickRight
+	LeftStickUp
+	LeftStickDown
+)
+
+var gamepadInputNames = map[GamepadInput]string{
+	GamepadInput(ebiten.StandardGamepadButtonRightBottom):      "A",
+	GamepadInput(ebiten.StandardGamepadButtonRightRight):       "B",
+	GamepadInput(ebiten.StandardGamepadButtonRightLeft):        "X",
+	GamepadInput(ebiten.StandardGamepadButtonRightTop):         "Y",
+	GamepadInput(ebiten.StandardGamepadButtonFrontTopLeft):     "LB",
+	GamepadInput(ebiten.StandardGamepadButtonFrontTopRight):    "RB",
+	GamepadInput(ebiten.StandardGamepadButtonFrontBottomLeft):  "LT",
+	GamepadInput(ebiten.StandardGamepadButtonFrontBottomRight): "RT",
+	GamepadInput(ebiten.StandardGamepadButtonCenterLeft):       "Back",
+	GamepadInput(ebiten.StandardGamepadButtonCenterRight):      "Start",
+	GamepadInput(ebiten.StandardGamepadButtonLeftStick):        "LS",
+	GamepadInput(ebiten.StandardGamepadButtonRightStick):       "RS",
+	GamepadInput(ebiten.StandardGamepadButtonLeftTop):          "DpadUp",
+	GamepadInput(ebiten.StandardGamepadButtonLeftBottom):       "DpadDown",
+	GamepadInput(ebiten.StandardGamepadButtonLeftLeft):         "DpadLeft",
+	GamepadInput(ebiten.StandardGamepadButtonLeftRight):        "DpadRight",
+	GamepadInput(ebiten.StandardGamepadButtonCenterCenter):     "Home",
+	LeftStickLeft:  "StickLeft",
+	LeftStickRight: "StickRight",
+	LeftStickUp:    "StickUp",
+	LeftStickDown:  "StickDown",
+}
+
+// gamepadInputs are the inputs that can be bound in a fixed order, the first one pressed is bound.
+var gamepadInputs = sortedKeys(gamepadInputNames)
+
+// the left button clicks the buttons of the pages, so it can't be bound
+var mouseButtonNames = map[ebiten.MouseButton]string{
+	ebiten.MouseButtonRight:  "Right",
+	ebiten.MouseButtonMiddle: "Middle",
+	ebiten.MouseButton3:      "Back",
+	ebiten.MouseButton4:      "Forward",
+}
+
+var mouseButtons = sortedKeys(mouseButtonNames)
+
+func sortedKeys[K ~int, V any](names map[K]V) []K {
+	keys := make([]K, 0, len(names))
+	for key := range names {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Binding is a key, a gamepad input or a mouse button an action is bound to.
+type Binding struct {
+	Device Device
+	Code   int
+}
+
+func Key(key ebiten.Key) Binding {
+	return Binding{Device: Keyboard, Code: int(key)}
+}
+
+func Pad(input GamepadInput) Binding {
+	return Binding{Device: Gamepad, Code: int(input)}
+}
+
+func PadButton(button ebiten.StandardGamepadButton) Binding {
+	return Pad(GamepadInput(button))
+}
+
+func MouseButton(button ebiten.MouseButton) Binding {
+	return Binding{Device: Mouse, Code: int(button)}
+}
+
+func (binding Binding) name() string {
+	switch binding.Device {
+	case Keyboard:
+		return ebiten.Key(binding.Code).String()
+	case Gamepad:
+		return gamepadInputNames[GamepadInput(binding.Code)]
+	case Mouse:
+		return mouseButtonNames[ebiten.MouseButton(binding.Code)]
+	}
+	return ""
+}
+
+// Title returns the binding as it is shown to the player, e.g. "Pad StickLeft".
+func (binding Binding) Title() string {
+	switch binding.Device {
+	case Gamepad:
+		return "Pad " + binding.name()
+	case Mouse:
+		return "Mouse " + binding.name()
+	}
+	return binding.name()
+}
+
+// MarshalText writes the binding to settings.json as the device and the name, e.g. "key:ArrowLeft" or "pad:A".
+func (binding Binding) MarshalText() ([]byte, error) {
+	name := binding.name()
+	if name == "" {
+		return nil, fmt.Errorf("unknown binding %d of device %d", binding.Code, binding.Device)
+	}
+	return []byte(devicePrefixes[binding.Device] + ":" + name), nil
+}
+
+func (binding *Binding) UnmarshalText(text []byte) error {
+	prefix, name, ok := strings.Cut(string(text), ":")
+	if !ok {
+		return fmt.Errorf("binding %q has no device", text)
+	}
+	switch prefix {
+	case devicePrefixes[Keyboard]:
+		var key ebiten.Key
+		if err := key.UnmarshalText([]byte(name)); err != nil {
+			return err
+		}
+		*binding = Key(key)
+		return nil
+	case devicePrefixes[Gamepad]:
+		for input, inputName := range gamepadInputNames {
+			if inputName == name {
+				*binding = Pad(input)
+				return nil
+			}
+		}
+	case devicePrefixes[Mouse]:
+		for button, buttonName := range mouseButtonNames {
+			if buttonName == name {
+				*binding = MouseButton(button)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("unknown binding %q", text)
+}
+
+// Bindings are what every action is bound to.
+type Bindings map[Action][]Binding
+
+func DefaultBindings() Bindings {
+	stick := func(direction GamepadInput, button ebiten.StandardGamepadButton) []Binding {
+		return []Binding{PadButton(button), Pad(direction)}
+	}
+	return Bindings{
+		SteerLeft:     append([]Binding{Key(ebiten.KeyLeft)}, stick(LeftStickLeft, ebiten.StandardGamepadButtonLeftLeft)...),
+		SteerRight:    append([]Binding{Key(ebiten.KeyRight)}, stick(LeftStickRight, ebiten.StandardGamepadButtonLeftRight)...),
+		Throttle:      {Key(ebiten.KeyUp), PadButton(ebiten.StandardGamepadButtonFrontBottomRight), PadButton(ebiten.StandardGamepadButtonLeftTop), Pad(LeftStickUp)},
+		Brake:         {Key(ebiten.KeyDown), PadButton(ebiten.StandardGamepadButtonFrontBottomLeft), PadButton(ebiten.StandardGamepadButtonLeftBottom), Pad(LeftStickDown)},
+		Pause:         {Key(ebiten.KeyEscape), PadButton(ebiten.StandardGamepadButtonCenterRight)},
+		MenuUp:        append([]Binding{Key(ebiten.KeyUp)}, stick(LeftStickUp, ebiten.StandardGamepadButtonLeftTop)...),
+		MenuDown:      append([]Binding{Key(ebiten.KeyDown)}, stick(LeftStickDown, ebiten.StandardGamepadButtonLeftBottom)...),
+		MenuLeft:      append([]Binding{Key(ebiten.KeyLeft)}, stick(LeftStickLeft, ebiten.StandardGamepadButtonLeftLeft)...),
+		MenuRight:     append([]Binding{Key(ebiten.KeyRight)}, stick(LeftStickRight, ebiten.StandardGamepadButtonLeftRight)...),
+		Confirm:       {Key(ebiten.KeyEnter), PadButton(ebiten.StandardGamepadButtonRightBottom)},
+		Back:          {Key(ebiten.KeyEscape), PadButton(ebiten.StandardGamepadButtonRightRight), PadButton(ebiten.StandardGamepadButtonCenterRight)},
+		SwitchView:    {Key(ebiten.KeyTab), PadButton(ebiten.StandardGamepadButtonRightTop)},
+		PreviousTrack: {Key(ebiten.KeyZ), PadButton(ebiten.StandardGamepadButtonFrontTopLeft)},
+		NextTrack:     {Key(ebiten.KeyX), PadButton(ebiten.StandardGamepadButtonFrontTopRight)},
+	}
+}
+
+// WithDefaults returns the bindings with the default ones of the actions that aren't bound,
+// e.g. the ones added after settings.json was written.
+func (bindings Bindings) WithDefaults() Bindings {
+	merged := DefaultBindings()
+	for action, bound := range bindings {
+		if action >= 0 && action < actionCount {
+			merged[action] = slices.Clone(bound)
+		}
+	}
+	return merged
+}
+
+func (bindings Bindings) Clone() Bindings {
+	cloned := maps.Clone(bindings)
+	for action, bound := range cloned {
+		cloned[action] = slices.Clone(bound)
+	}
+	return cloned
+}
+
+// Conflict returns the other action that is used in the same place as the action and is bound to the binding.
+func (bindings Bindings) Conflict(action Action, binding Binding) (Action, bool) {
+	for _, other := range Actions() {
+		if other != action && action.shares(other) && slices.Contains(bindings[other], binding) {
+			return other, true
+		}
+	}
+	return 0, false
+}
+
+// Bind binds the action to the binding instead of the other bindings of its device. The binding is taken
+// from the actions it is in conflict with.
+func (bindings Bindings) Bind(action Action, binding Binding) {
+	for other, bound := range bindings {
+		if other != action && action.shares(other) {
+			bindings[other] = slices.DeleteFunc(bound, func(taken Binding) bool {
+				return taken == binding
+			})
+		}
+	}
+	bindings[action] = append(slices.DeleteFunc(bindings[action], func(bound Binding) bool {
+		return bound.Device == binding.Device
+	}), binding)
+}
+
+// Titles returns the bindings of the action as they are shown to the player.
+func (bindings Bindings) Titles(action Action) string {
+	if len(bindings[action]) == 0 {
+		return "not bound"
+	}
+	titles := make([]string, len(bindings[action]))
+	for i, binding := range bindings[action] {
+		titles[i] = binding.Title()
+	}
+	return strings.Join(titles, ", ")
+}
@@ -0,0 +1,171 @@
+package input
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// EventManager runs the events of the actions whose bindings are held or were just pressed,
+// the keyboard, every gamepad and the mouse can do every action.
+type EventManager struct {
+	bindings Bindings
+	gamepads *Gamepads
+	// held are the actions held in this tick, wasHeld the ones held in the tick before
+	held    [actionCount]bool
+	wasHeld [actionCount]bool
+	// padHeld are the gamepad inputs held in this tick, to tell the ones that were just pressed to the listener
+	padHeld       map[Binding]bool
+	padWasHeld    map[Binding]bool
+	listener      func(binding Binding)
+	eventsPress   map[Action][]func()
+	eventsPressed map[Action][]func()
+}
+
+func NewEventManager(bindings Bindings, gamepads *Gamepads) *EventManager {
+	return &EventManager{
+		bindings:      bindings,
+		gamepads:      gamepads,
+		padHeld:       make(map[Binding]bool),
+		padWasHeld:    make(map[Binding]bool),
+		eventsPress:   make(map[Action][]func()),
+		eventsPressed: make(map[Action][]func()),
+	}
+}
+
+func (eventManager *EventManager) SetBindings(bindings Bindings) {
+	eventManager.bindings = bindings
+}
+
+// Update runs the events of the actions in their order, the rest are left out once interrupted returns true,
+// e.g. because an event has left the page. No events run while a listener waits for a binding.
+func (eventManager *EventManager) Update(interrupted func() bool) {
+	eventManager.wasHeld = eventManager.held
+	for action := range actionCount {
+		eventManager.held[action] = eventManager.isHeld(action, func(Binding) bool { return true })
+	}
+	eventManager.padHeld, eventManager.padWasHeld = eventManager.padWasHeld, eventManager.padHeld
+	clear(eventManager.padHeld)
+	eventManager.gamepads.each(func(id ebiten.GamepadID) {
+		for input := range gamepadInputNames {
+			if gamepadInputHeld(id, input) {
+				eventManager.padHeld[Pad(input)] = true
+			}
+		}
+	})
+
+	if eventManager.listener != nil {
+		if binding, ok := eventManager.justPressedBinding(); ok {
+			listener := eventManager.listener
+			eventManager.listener = nil
+			listener(binding)
+		}
+		return
+	}
+
+	for action := range actionCount {
+		if eventManager.held[action] {
+			for _, event := range eventManager.eventsPress[action] {
+				event()
+			}
+		}
+		if eventManager.held[action] && !eventManager.wasHeld[action] {
+			for _, event := range eventManager.eventsPressed[action] {
+				event()
+			}
+		}
+		if interrupted() {
+			return
+		}
+	}
+}
+
+// Listen passes the next key, gamepad input or mouse button that is pressed to the listener instead of
+// running the events, e.g. to bind it to an action.
+func (eventManager *EventManager) Listen(listener func(binding Binding)) {
+	eventManager.listener = listener
+}
+
+func (eventManager *EventManager) StopListening() {
+	eventManager.listener = nil
+}
+
+func (eventManager *EventManager) Listening() bool {
+	return eventManager.listener != nil
+}
+
+func (eventManager *EventManager) justPressedBinding() (Binding, bool) {
+	if keys := inpututil.AppendJustPressedKeys(nil); len(keys) > 0 {
+		return Key(keys[0]), true
+	}
+	for _, input := range gamepadInputs {
+		binding := Pad(input)
+		if eventManager.padHeld[binding] && !eventManager.padWasHeld[binding] {
+			return binding, true
+		}
+	}
+	for _, button := range mouseButtons {
+		if inpututil.IsMouseButtonJustPressed(button) {
+			return MouseButton(button), true
+		}
+	}
+	return Binding{}, false
+}
+
+// Held reports whether the action is held with any device.
+func (eventManager *EventManager) Held(action Action) bool {
+	return eventManager.held[action]
+}
+
+// HeldWithKeyboardOrMouse reports whether the action is held without a gamepad, e.g. by the player
+// who shares the keyboard with another one on a gamepad.
+func (eventManager *EventManager) HeldWithKeyboardOrMouse(action Action) bool {
+	return eventManager.isHeld(action, func(binding Binding) bool {
+		return binding.Device != Gamepad
+	})
+}
+
+// HeldWithGamepad reports whether the action is held with the gamepad.
+func (eventManager *EventManager) HeldWithGamepad(action Action, id ebiten.GamepadID) bool {
+	for _, binding := range eventManager.bindings[action] {
+		if binding.Device == Gamepad && gamepadInputHeld(id, GamepadInput(binding.Code)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isHeld reports whether one of the bindings of the action that pass the filter is held.
+func (eventManager *EventManager) isHeld(action Action, filter func(binding Binding) bool) bool {
+	for _, binding := range eventManager.bindings[action] {
+		if !filter(binding) {
+			continue
+		}
+		switch binding.Device {
+		case Keyboard:
+			if ebiten.IsKeyPressed(ebiten.Key(binding.Code)) {
+				return true
+			}
+		case Gamepad:
+			held := false
+			eventManager.gamepads.each(func(id ebiten.GamepadID) {
+				held = held || gamepadInputHeld(id, GamepadInput(binding.Code))
+			})
+			if held {
+				return true
+			}
+		case Mouse:
+			if ebiten.IsMouseButtonPressed(ebiten.MouseButton(binding.Code)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (eventManager *EventManager) AddPressEvent(action Action, event func()) {
+	eventManager.eventsPress[action] = append(eventManager.eventsPress[action], event)
+}
+
+func (eventManager *EventManager) AddPressedEvent(action Action, event func()) {
+	eventManager.eventsPressed[action] = append(eventManager.eventsPressed[action], event)
+}
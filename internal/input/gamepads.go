@@ -0,0 +1,78 @@
+package input
+
+import (
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	StickDeadZone    = 0.4
+	triggerThreshold = 0.3
+	noGamepad        = ebiten.GamepadID(-1)
+)
+
+// Gamepads gives every connected gamepad a player. A gamepad that is unplugged leaves its player without one
+// until the next gamepad is plugged in, so the other players keep theirs.
+type Gamepads struct {
+	players []ebiten.GamepadID
+}
+
+// Update handles the gamepads plugged in and out since the last tick and returns them.
+func (gamepads *Gamepads) Update() (connected, disconnected []ebiten.GamepadID) {
+	for player, id := range gamepads.players {
+		if id != noGamepad && inpututil.IsGamepadJustDisconnected(id) {
+			gamepads.players[player] = noGamepad
+			disconnected = append(disconnected, id)
+		}
+	}
+	connected = inpututil.AppendJustConnectedGamepadIDs(nil)
+	for _, id := range connected {
+		if slices.Contains(gamepads.players, id) {
+			continue
+		}
+		if player := slices.Index(gamepads.players, noGamepad); player >= 0 {
+			gamepads.players[player] = id
+		} else {
+			gamepads.players = append(gamepads.players, id)
+		}
+	}
+	return connected, disconnected
+}
+
+// Gamepad returns the gamepad of the player, false if they have none with the standard layout.
+func (gamepads *Gamepads) Gamepad(player int) (ebiten.GamepadID, bool) {
+	if player >= len(gamepads.players) || gamepads.players[player] == noGamepad {
+		return 0, false
+	}
+	id := gamepads.players[player]
+	return id, ebiten.IsStandardGamepadLayoutAvailable(id)
+}
+
+// each runs the function for every gamepad with the standard layout.
+func (gamepads *Gamepads) each(function func(id ebiten.GamepadID)) {
+	for player := range gamepads.players {
+		if id, ok := gamepads.Gamepad(player); ok {
+			function(id)
+		}
+	}
+}
+
+// gamepadInputHeld reports whether the input of the gamepad is held, the triggers when they are pushed
+// past a third and the stick when it is pushed out of the dead zone.
+func gamepadInputHeld(id ebiten.GamepadID, input GamepadInput) bool {
+	horizontal := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+	vertical := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical)
+	switch input {
+	case LeftStickLeft:
+		return horizontal < -StickDeadZone
+	case LeftStickRight:
+		return horizontal > StickDeadZone
+	case LeftStickUp:
+		return vertical < -StickDeadZone
+	case LeftStickDown:
+		return vertical > StickDeadZone
+	}
+	return ebiten.StandardGamepadButtonValue(id, ebiten.StandardGamepadButton(input)) > triggerThreshold
+}
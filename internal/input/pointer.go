@@ -0,0 +1,71 @@
+package input
+
+import (
+	"image"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// PointerMode is how the mouse or the touch screen steers the car.
+type PointerMode string
+
+const (
+	PointerOff   PointerMode = "Off"
+	PointerMouse PointerMode = "Follow the mouse"
+	PointerTouch PointerMode = "Touch zones"
+)
+
+var PointerModes = []PointerMode{PointerOff, PointerMouse, PointerTouch}
+
+// followDeadZone is how far the car can be from the cursor without steering, so it doesn't shake around it.
+const followDeadZone = 12
+
+// Zone is a part of the screen that steers the car in the direction while it is touched.
+type Zone struct {
+	Name      string
+	Bounds    image.Rectangle
+	Direction ebiten.Key
+}
+
+// TouchZones returns the zones of a screen of the size: its left and right thirds steer and the middle one brakes.
+func TouchZones(width, height int) []Zone {
+	third := width / 3
+	return []Zone{
+		{Name: "Left", Bounds: image.Rect(0, 0, third, height), Direction: ebiten.KeyLeft},
+		{Name: "Brake", Bounds: image.Rect(third, 0, width-third, height), Direction: ebiten.KeyDown},
+		{Name: "Right", Bounds: image.Rect(width-third, 0, width, height), Direction: ebiten.KeyRight},
+	}
+}
+
+// PointerDirections returns the directions the pointer steers a car with its middle at carX on a screen of the size,
+// as the arrow keys Player.Move understands. Following the mouse, the car steers toward the cursor no faster
+// than its own steering, so it can't jump to it. Every touch steers with the zone it is in, so a car can be
+// steered and braked with two fingers.
+func PointerDirections(mode PointerMode, carX float64, width, height int) []ebiten.Key {
+	var directions []ebiten.Key
+	switch mode {
+	case PointerMouse:
+		if !ebiten.IsFocused() {
+			return nil
+		}
+		x, _ := ebiten.CursorPosition()
+		switch {
+		case float64(x) < carX-followDeadZone:
+			directions = append(directions, ebiten.KeyLeft)
+		case float64(x) > carX+followDeadZone:
+			directions = append(directions, ebiten.KeyRight)
+		}
+	case PointerTouch:
+		zones := TouchZones(width, height)
+		for _, id := range ebiten.AppendTouchIDs(nil) {
+			position := image.Pt(ebiten.TouchPosition(id))
+			for _, zone := range zones {
+				if position.In(zone.Bounds) && !slices.Contains(directions, zone.Direction) {
+					directions = append(directions, zone.Direction)
+				}
+			}
+		}
+	}
+	return directions
+}
@@ -0,0 +1,61 @@
+package leaderboardserver
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter lets every client make burst requests at once and refills one of them every interval.
+type rateLimiter struct {
+	mutex    sync.Mutex
+	burst    float64
+	interval time.Duration
+	buckets  map[string]*bucket
+	cleaned  time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func newRateLimiter(burst int, interval time.Duration) *rateLimiter {
+	return &rateLimiter{
+		burst:    float64(burst),
+		interval: interval,
+		buckets:  make(map[string]*bucket),
+	}
+}
+
+func (limiter *rateLimiter) allow(client string, now time.Time) bool {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	limiter.clean(now)
+
+	clientBucket, ok := limiter.buckets[client]
+	if !ok {
+		clientBucket = &bucket{tokens: limiter.burst, updated: now}
+		limiter.buckets[client] = clientBucket
+	}
+	clientBucket.tokens = min(limiter.burst, clientBucket.tokens+float64(now.Sub(clientBucket.updated))/float64(limiter.interval))
+	clientBucket.updated = now
+	if clientBucket.tokens < 1 {
+		return false
+	}
+	clientBucket.tokens--
+	return true
+}
+
+// clean forgets the clients whose buckets are full again, the mutex must be held.
+func (limiter *rateLimiter) clean(now time.Time) {
+	full := time.Duration(limiter.burst) * limiter.interval
+	if now.Sub(limiter.cleaned) < full {
+		return
+	}
+	limiter.cleaned = now
+	for client, clientBucket := range limiter.buckets {
+		if now.Sub(clientBucket.updated) >= full {
+			delete(limiter.buckets, client)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package leaderboardserver
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/VxVxN/game/pkg/replay"
+	"github.com/VxVxN/game/pkg/statisticer"
+)
+
+const (
+	defaultLimit  = 10
+	maxLimit      = 100
+	maxNameLength = 32
+	maxBodySize   = 1 << 20 // the replay of a long run
+)
+
+type Config struct {
+	// AdminToken protects the admin endpoints, they are off while it is empty.
+	AdminToken string
+	// SubmitBurst submissions can be made at once by one address, then one more every SubmitInterval.
+	SubmitBurst    int
+	SubmitInterval time.Duration
+	// Verify drives the replay of a submission again and fails unless it ends with the claimed points.
+	// Submissions are taken as they are while it is nil.
+	Verify func(run *replay.Replay) error
+}
+
+var DefaultConfig = Config{SubmitBurst: 5, SubmitInterval: 10 * time.Second}
+
+// Server serves the leaderboards:
+//
+//	POST   /scores              submits a record
+//	GET    /scores              the best records, filtered by mode, seed and day (YYYY-MM-DD) and cut to limit
+//	DELETE /admin/scores/{id}   removes an entry
+//	DELETE /admin/scores?name=  removes every entry of the player
+type Server struct {
+	store   *Store
+	config  Config
+	limiter *rateLimiter
+	logger  *slog.Logger
+	now     func() time.Time
+}
+
+func New(store *Store, config Config, logger *slog.Logger) *Server {
+	return &Server{
+		store:   store,
+		config:  config,
+		limiter: newRateLimiter(config.SubmitBurst, config.SubmitInterval),
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+func (server *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /scores", server.submit)
+	mux.HandleFunc("GET /scores", server.top)
+	mux.HandleFunc("DELETE /admin/scores/{id}", server.admin(server.deleteEntry))
+	mux.HandleFunc("DELETE /admin/scores", server.admin(server.deletePlayer))
+	return mux
+}
+
+func (server *Server) submit(w http.ResponseWriter, r *http.Request) {
+	if !server.limiter.allow(clientAddress(r), server.now()) {
+		http.Error(w, "too many submissions, try again later", http.StatusTooManyRequests)
+		return
+	}
+	var record statisticer.Record
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&record); err != nil {
+		http.Error(w, fmt.Sprintf("invalid record: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := validate(record); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if server.config.Verify != nil {
+		err := server.verify(record)
+		if errors.Is(err, ErrBusy) {
+			server.logger.Warn("Record not verified, the verifier is busy", "name", record.Name, "points", record.Points, "mode", record.Mode)
+			http.Error(w, "too many scores are being verified, try again later", http.StatusServiceUnavailable)
+			return
+		}
+		if err != nil {
+			server.logger.Info("Record rejected", "name", record.Name, "points", record.Points, "mode", record.Mode, "reason", err)
+			http.Error(w, fmt.Sprintf("the score can't be verified: %v", err), http.StatusBadRequest)
+			return
+		}
+		// the replay is what was verified, not what the client says about it
+		record.Difficulty = replay.Difficulty(record.Replay.Speed)
+	}
+	record.Replay = nil // only needed to verify the score
+	entry, err := server.store.Add(record, server.now())
+	if err != nil {
+		server.logger.Error("Failed to add the record", "error", err)
+		http.Error(w, "failed to store the record", http.StatusInternalServerError)
+		return
+	}
+	server.logger.Info("Record submitted", "id", entry.ID, "name", entry.Name, "points", entry.Points, "mode", entry.Mode)
+	writeJSON(w, http.StatusCreated, entry)
+}
+
+func validate(record statisticer.Record) error {
+	switch {
+	case strings.TrimSpace(record.Name) == "":
+		return fmt.Errorf("the name is empty")
+	case utf8.RuneCountInString(record.Name) > maxNameLength:
+		return fmt.Errorf("the name is longer than %d characters", maxNameLength)
+	case record.Points < 0:
+		return fmt.Errorf("the points are negative")
+	case record.Mode == "":
+		return fmt.Errorf("the mode is empty")
+	}
+	return nil
+}
+
+// verify checks that the replay belongs to the record and drives it again.
+func (server *Server) verify(record statisticer.Record) error {
+	run := record.Replay
+	switch {
+	case run == nil:
+		return fmt.Errorf("the replay is missing")
+	case run.Mode != record.Mode || run.Seed != record.Seed || run.Points != record.Points:
+		return fmt.Errorf("the replay is of another run")
+	}
+	if err := run.Validate(); err != nil {
+		return err
+	}
+	return server.config.Verify(run)
+}
+
+func (server *Server) top(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := Filter{Mode: query.Get("mode"), Day: query.Get("day")}
+	if filter.Day == "today" {
+		filter.Day = server.now().UTC().Format(dayLayout)
+	} else if filter.Day != "" {
+		if _, err := time.Parse(dayLayout, filter.Day); err != nil {
+			http.Error(w, "the day must look like 2006-01-02", http.StatusBadRequest)
+			return
+		}
+	}
+	if value := query.Get("seed"); value != "" {
+		seed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid seed", http.StatusBadRequest)
+			return
+		}
+		filter.Seed = &seed
+	}
+	limit := defaultLimit
+	if value := query.Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = min(limit, maxLimit)
+	}
+	entries := server.store.Top(filter, limit)
+	if entries == nil {
+		entries = []Entry{}
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// admin lets the request through only with the admin token in the Authorization header.
+func (server *Server) admin(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if server.config.AdminToken == "" || !ok || subtle.ConstantTimeCompare([]byte(token), []byte(server.config.AdminToken)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+func (server *Server) deleteEntry(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	removed, err := server.store.Delete(id)
+	if err != nil {
+		server.logger.Error("Failed to delete the entry", "id", id, "error", err)
+		http.Error(w, "failed to delete the entry", http.StatusInternalServerError)
+		return
+	}
+	if !removed {
+		http.Error(w, "no such entry", http.StatusNotFound)
+		return
+	}
+	server.logger.Info("Entry deleted", "id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (server *Server) deletePlayer(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "the name is empty", http.StatusBadRequest)
+		return
+	}
+	removed, err := server.store.DeleteMatching(func(entry Entry) bool {
+		return entry.Name == name
+	})
+	if err != nil {
+		server.logger.Error("Failed to delete the entries", "name", name, "error", err)
+		http.Error(w, "failed to delete the entries", http.StatusInternalServerError)
+		return
+	}
+	server.logger.Info("Entries deleted", "name", name, "count", removed)
+	writeJSON(w, http.StatusOK, map[string]int{"Deleted": removed})
+}
+
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
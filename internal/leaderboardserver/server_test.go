@@ -0,0 +1,351 @@
+package leaderboardserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/VxVxN/game/pkg/replay"
+	"github.com/VxVxN/game/pkg/statisticer"
+)
+
+const adminToken = "secret"
+
+// testServer serves a store in a temporary directory, its clock only moves when the test moves it.
+type testServer struct {
+	*Server
+	http  *httptest.Server
+	path  string
+	clock time.Time
+}
+
+func newTestServer(t *testing.T, config Config) *testServer {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "leaderboard.json")
+	store, err := OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &testServer{
+		Server: New(store, config, slog.New(slog.NewTextHandler(io.Discard, nil))),
+		path:   path,
+		clock:  time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+	}
+	server.now = func() time.Time { return server.clock }
+	server.http = httptest.NewServer(server.Handler())
+	t.Cleanup(server.http.Close)
+	return server
+}
+
+func (server *testServer) do(t *testing.T, method, path, token string, body any) *http.Response {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	request, err := http.NewRequest(method, server.http.URL+path, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { response.Body.Close() })
+	return response
+}
+
+func (server *testServer) submit(t *testing.T, record statisticer.Record) int {
+	t.Helper()
+	return server.do(t, http.MethodPost, "/scores", "", record).StatusCode
+}
+
+func (server *testServer) top(t *testing.T, query string) []Entry {
+	t.Helper()
+	response := server.do(t, http.MethodGet, "/scores?"+query, "", nil)
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("GET /scores?%s is %s", query, response.Status)
+	}
+	var entries []Entry
+	if err := json.NewDecoder(response.Body).Decode(&entries); err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+func record(name string, points int, mode string, seed uint64) statisticer.Record {
+	record := statisticer.NewRecord(name, points, "City")
+	record.Mode = mode
+	record.Seed = seed
+	return record
+}
+
+func names(entries []Entry) []string {
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name
+	}
+	return names
+}
+
+func TestSubmitValidates(t *testing.T) {
+	server := newTestServer(t, Config{SubmitBurst: 10, SubmitInterval: time.Second})
+	long := record("", 1, "endless", 1)
+	for range maxNameLength + 1 {
+		long.Name += "a"
+	}
+	tests := []struct {
+		name   string
+		record statisticer.Record
+		status int
+	}{
+		{"valid", record("Player", 100, "endless", 1), http.StatusCreated},
+		{"no name", record(" ", 100, "endless", 1), http.StatusBadRequest},
+		{"long name", long, http.StatusBadRequest},
+		{"negative points", record("Player", -1, "endless", 1), http.StatusBadRequest},
+		{"no mode", record("Player", 100, "", 1), http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		if status := server.submit(t, test.record); status != test.status {
+			t.Errorf("%s: the status is %d, want %d", test.name, status, test.status)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	server := newTestServer(t, Config{SubmitBurst: 2, SubmitInterval: 10 * time.Second})
+	for i := range 2 {
+		if status := server.submit(t, record("Player", i, "endless", 1)); status != http.StatusCreated {
+			t.Fatalf("submission %d is %d", i, status)
+		}
+	}
+	if status := server.submit(t, record("Player", 3, "endless", 1)); status != http.StatusTooManyRequests {
+		t.Fatalf("a submission past the burst is %d, want 429", status)
+	}
+	server.clock = server.clock.Add(10 * time.Second)
+	if status := server.submit(t, record("Player", 4, "endless", 1)); status != http.StatusCreated {
+		t.Fatalf("a submission after the interval is %d", status)
+	}
+	if status := server.submit(t, record("Player", 5, "endless", 1)); status != http.StatusTooManyRequests {
+		t.Fatalf("a second submission after one interval is %d, want 429", status)
+	}
+}
+
+func TestTopFilters(t *testing.T) {
+	server := newTestServer(t, Config{SubmitBurst: 10, SubmitInterval: time.Second})
+	server.submit(t, record("Zero", 300, "endless", 0))
+	server.submit(t, record("Seven", 200, "endless", 7))
+	server.submit(t, record("Zen", 500, "zen", 0))
+	server.clock = server.clock.Add(24 * time.Hour)
+	server.submit(t, record("Tomorrow", 100, "endless", 0))
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Zen", "Zero", "Seven", "Tomorrow"}},
+		{"limit=2", []string{"Zen", "Zero"}},
+		{"mode=endless", []string{"Zero", "Seven", "Tomorrow"}},
+		{"mode=endless&seed=0", []string{"Zero", "Tomorrow"}},
+		{"seed=7", []string{"Seven"}},
+		{"day=2026-10-19", []string{"Zen", "Zero", "Seven"}},
+		{"day=today&mode=endless", []string{"Tomorrow"}},
+		{"mode=pursuit", []string{}},
+	}
+	for _, test := range tests {
+		if got := names(server.top(t, test.query)); !slices.Equal(got, test.want) {
+			t.Errorf("?%s has %v, want %v", test.query, got, test.want)
+		}
+	}
+	for _, query := range []string{"seed=x", "day=19.10.2026", "limit=0"} {
+		if response := server.do(t, http.MethodGet, "/scores?"+query, "", nil); response.StatusCode != http.StatusBadRequest {
+			t.Errorf("?%s is %s, want 400", query, response.Status)
+		}
+	}
+}
+
+func TestAdmin(t *testing.T) {
+	server := newTestServer(t, Config{AdminToken: adminToken, SubmitBurst: 10, SubmitInterval: time.Second})
+	server.submit(t, record("Cheater", 900, "endless", 1))
+	server.submit(t, record("Cheater", 800, "zen", 1))
+	server.submit(t, record("Player", 100, "endless", 1))
+	id := server.top(t, "mode=endless")[1].ID
+
+	for _, token := range []string{"", "wrong"} {
+		if response := server.do(t, http.MethodDelete, "/admin/scores/"+id, token, nil); response.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("a delete with the token %q is %s", token, response.Status)
+		}
+	}
+	if response := server.do(t, http.MethodDelete, "/admin/scores/"+id, adminToken, nil); response.StatusCode != http.StatusNoContent {
+		t.Fatalf("the delete is %s", response.Status)
+	}
+	if response := server.do(t, http.MethodDelete, "/admin/scores/"+id, adminToken, nil); response.StatusCode != http.StatusNotFound {
+		t.Fatalf("a second delete is %s", response.Status)
+	}
+	response := server.do(t, http.MethodDelete, "/admin/scores?name=Cheater", adminToken, nil)
+	var deleted map[string]int
+	if err := json.NewDecoder(response.Body).Decode(&deleted); err != nil {
+		t.Fatal(err)
+	}
+	if deleted["Deleted"] != 2 {
+		t.Fatalf("%d entries of the player are deleted, want 2", deleted["Deleted"])
+	}
+	if got := names(server.top(t, "")); len(got) != 0 {
+		t.Fatalf("the leaderboard still has %v", got)
+	}
+}
+
+func TestAdminOff(t *testing.T) {
+	server := newTestServer(t, Config{SubmitBurst: 10, SubmitInterval: time.Second})
+	if response := server.do(t, http.MethodDelete, "/admin/scores?name=Player", "", nil); response.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("the admin endpoint without a token is %s", response.Status)
+	}
+}
+
+func TestStorePersists(t *testing.T) {
+	server := newTestServer(t, Config{SubmitBurst: 10, SubmitInterval: time.Second})
+	server.submit(t, record("First", 100, "endless", 1))
+	server.submit(t, record("Second", 200, "endless", 1))
+
+	store, err := OpenStore(server.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := store.Top(Filter{}, 10)
+	if got := names(entries); !slices.Equal(got, []string{"Second", "First"}) {
+		t.Fatalf("the reopened store has %v", got)
+	}
+	if !entries[0].Submitted.Equal(server.clock) {
+		t.Fatalf("the entry was submitted at %v, want %v", entries[0].Submitted, server.clock)
+	}
+	if removed, err := store.Delete(entries[0].ID); err != nil || !removed {
+		t.Fatalf("the delete has removed %v: %v", removed, err)
+	}
+	if store, err = OpenStore(server.path); err != nil {
+		t.Fatal(err)
+	}
+	if got := names(store.Top(Filter{}, 10)); !slices.Equal(got, []string{"First"}) {
+		t.Fatalf("the store has %v after the delete", got)
+	}
+}
+
+func TestOpenCorruptStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leaderboard.json")
+	if err := os.WriteFile(path, []byte(`[{"ID":`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenStore(path); err == nil {
+		t.Fatal("a corrupt store is opened")
+	}
+}
+
+func verifiedRecord(speed float64) statisticer.Record {
+	record := record("Player", 100, "endless", 1)
+	record.Difficulty = "sensitivity 1.0"
+	record.Replay = &replay.Replay{
+		Mode:         "endless",
+		Seed:         1,
+		Speed:        speed,
+		ScreenWidth:  1920,
+		ScreenHeight: 1080,
+		Width:        1920,
+		Height:       1080,
+		Inputs:       []replay.Span{{Ticks: 60}},
+		Points:       100,
+	}
+	return record
+}
+
+func TestVerify(t *testing.T) {
+	var verified int
+	server := newTestServer(t, Config{SubmitBurst: 10, SubmitInterval: time.Second, Verify: func(run *replay.Replay) error {
+		verified++
+		return nil
+	}})
+
+	if status := server.submit(t, record("Player", 100, "endless", 1)); status != http.StatusBadRequest {
+		t.Fatalf("a record without a replay is %d", status)
+	}
+	other := verifiedRecord(7)
+	other.Points = 200
+	if status := server.submit(t, other); status != http.StatusBadRequest {
+		t.Fatalf("a record with the replay of another run is %d", status)
+	}
+	if status := server.submit(t, verifiedRecord(100)); status != http.StatusBadRequest {
+		t.Fatalf("a replay with a steering speed the settings don't allow is %d", status)
+	}
+	if verified != 0 {
+		t.Fatalf("%d invalid replays were driven", verified)
+	}
+
+	if status := server.submit(t, verifiedRecord(7)); status != http.StatusCreated {
+		t.Fatalf("a valid replay is %d", status)
+	}
+	entries := server.top(t, "")
+	if len(entries) != 1 || entries[0].Replay != nil {
+		t.Fatalf("the leaderboard has %+v", entries)
+	}
+	if entries[0].Difficulty != replay.Difficulty(7) {
+		t.Fatalf("the difficulty is %q, want the one of the replay", entries[0].Difficulty)
+	}
+}
+
+func TestVerifyFails(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{errors.New("the run ends with 90 points"), http.StatusBadRequest},
+		{ErrBusy, http.StatusServiceUnavailable},
+	}
+	for _, test := range tests {
+		server := newTestServer(t, Config{SubmitBurst: 10, SubmitInterval: time.Second, Verify: func(run *replay.Replay) error {
+			return test.err
+		}})
+		if status := server.submit(t, verifiedRecord(7)); status != test.status {
+			t.Errorf("%v: the status is %d, want %d", test.err, status, test.status)
+		}
+		if entries := server.top(t, ""); len(entries) != 0 {
+			t.Errorf("%v: the leaderboard has %d entries", test.err, len(entries))
+		}
+	}
+}
+
+func TestCommandVerifierBusy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake racer is a shell script")
+	}
+	racer := filepath.Join(t.TempDir(), "racer")
+	if err := os.WriteFile(racer, []byte("#!/bin/sh\nsleep 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	verify := CommandVerifier(racer, t.TempDir(), 1, 200*time.Millisecond)
+	run := verifiedRecord(7).Replay
+
+	first := make(chan error)
+	go func() { first <- verify(run) }()
+	time.Sleep(50 * time.Millisecond) // the first replay takes the only slot
+	if err := verify(run); !errors.Is(err, ErrBusy) {
+		t.Fatalf("the second replay got %v, want ErrBusy", err)
+	}
+	if err := <-first; err == nil {
+		t.Fatal("the first replay has passed although the racer took longer than the timeout")
+	}
+}
@@ -0,0 +1,153 @@
+package leaderboardserver
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/VxVxN/game/pkg/atomicfile"
+	"github.com/VxVxN/game/pkg/statisticer"
+)
+
+const dayLayout = "2006-01-02"
+
+// Entry is a record as the server keeps it, the record fields are sent inline so the game reads entries as records.
+type Entry struct {
+	ID string
+	statisticer.Record
+	Submitted time.Time
+}
+
+// Day returns the UTC day the entry was submitted on, the daily leaderboards are grouped by it.
+func (entry Entry) Day() string {
+	return entry.Submitted.UTC().Format(dayLayout)
+}
+
+// Filter selects the entries of a leaderboard, empty fields match everything.
+type Filter struct {
+	Mode string
+	Seed *uint64
+	Day  string
+	Name string
+}
+
+func (filter Filter) match(entry Entry) bool {
+	return (filter.Mode == "" || entry.Mode == filter.Mode) &&
+		(filter.Seed == nil || entry.Seed == *filter.Seed) &&
+		(filter.Day == "" || entry.Day() == filter.Day) &&
+		(filter.Name == "" || entry.Name == filter.Name)
+}
+
+// Store keeps every entry in memory and writes all of them to a JSON file after each change.
+type Store struct {
+	path    string
+	mutex   sync.Mutex
+	entries []Entry
+}
+
+func OpenStore(path string) (*Store, error) {
+	store := &Store{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store: %v", err)
+	}
+	if err = json.Unmarshal(data, &store.entries); err != nil {
+		return nil, fmt.Errorf("failed to decode store: %v", err)
+	}
+	return store, nil
+}
+
+func (store *Store) Add(record statisticer.Record, submitted time.Time) (Entry, error) {
+	id, err := newID()
+	if err != nil {
+		return Entry{}, err
+	}
+	entry := Entry{ID: id, Record: record, Submitted: submitted}
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.entries = append(store.entries, entry)
+	if err = store.save(); err != nil {
+		store.entries = store.entries[:len(store.entries)-1]
+		return Entry{}, err
+	}
+	return entry, nil
+}
+
+// Top returns up to limit entries matching the filter with the most points first.
+func (store *Store) Top(filter Filter, limit int) []Entry {
+	store.mutex.Lock()
+	var entries []Entry
+	for _, entry := range store.entries {
+		if filter.match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	store.mutex.Unlock()
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Points > entries[j].Points
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+// Delete removes the entry with the id and reports whether there was one.
+func (store *Store) Delete(id string) (bool, error) {
+	removed, err := store.DeleteMatching(func(entry Entry) bool {
+		return entry.ID == id
+	})
+	return removed > 0, err
+}
+
+// DeleteMatching removes the entries the function selects, e.g. every entry of a cheater, and returns how many there were.
+func (store *Store) DeleteMatching(selected func(entry Entry) bool) (int, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	kept := make([]Entry, 0, len(store.entries))
+	for _, entry := range store.entries {
+		if !selected(entry) {
+			kept = append(kept, entry)
+		}
+	}
+	removed := len(store.entries) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	previous := store.entries
+	store.entries = kept
+	if err := store.save(); err != nil {
+		store.entries = previous
+		return 0, err
+	}
+	return removed, nil
+}
+
+// save writes the entries to a temporary file first so a crash can't leave half a store behind, the mutex must be held.
+func (store *Store) save() error {
+	data, err := json.Marshal(store.entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal store: %v", err)
+	}
+	if err = atomicfile.Write(store.path, data); err != nil {
+		return fmt.Errorf("failed to save store: %v", err)
+	}
+	return nil
+}
+
+func newID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate id: %v", err)
+	}
+	return hex.EncodeToString(id), nil
+}
@@ -0,0 +1,64 @@
+package leaderboardserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/VxVxN/game/pkg/replay"
+)
+
+// ErrBusy is returned by a verifier that can't take another replay before its timeout.
+var ErrBusy = errors.New("the verifier is busy")
+
+// CommandVerifier verifies replays with `racer verify`. The game can't be linked into the server because
+// it needs a graphics driver, so the racer binary runs in dir, where it finds its assets.
+// At most parallel replays are verified at once, a replay that waits for its turn longer than timeout gets ErrBusy.
+func CommandVerifier(racer, dir string, parallel int, timeout time.Duration) func(run *replay.Replay) error {
+	slots := make(chan struct{}, max(parallel, 1))
+	return func(run *replay.Replay) error {
+		wait := time.NewTimer(timeout)
+		defer wait.Stop()
+		select {
+		case slots <- struct{}{}:
+		case <-wait.C:
+			return ErrBusy
+		}
+		defer func() { <-slots }()
+
+		file, err := os.CreateTemp("", "replay-*.json")
+		if err != nil {
+			return fmt.Errorf("failed to create replay file: %v", err)
+		}
+		file.Close()
+		defer os.Remove(file.Name())
+		if err = run.Save(file.Name()); err != nil {
+			return err
+		}
+		path, err := filepath.Abs(file.Name())
+		if err != nil {
+			return fmt.Errorf("failed to find replay file: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		command := exec.CommandContext(ctx, racer, "verify", path)
+		command.Dir = dir
+		var output bytes.Buffer
+		command.Stdout = &output
+		command.Stderr = &output
+		if err = command.Run(); err != nil {
+			if message := strings.TrimSpace(output.String()); message != "" {
+				return fmt.Errorf("%s", message)
+			}
+			return fmt.Errorf("failed to verify: %v", err)
+		}
+		return nil
+	}
+}
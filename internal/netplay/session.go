@@ -0,0 +1,491 @@
+package netplay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	MaxPlayers = 4
+	// InputDelay is how many ticks ahead the local input is scheduled, it hides the round trip on a LAN.
+	InputDelay   = 4
+	dialTimeout  = 5 * time.Second
+	writeTimeout = 5 * time.Second
+	// outboxSize is how many messages wait for a peer that reads slowly before it is dropped
+	outboxSize = 1024
+)
+
+// Input is the steering of one player in one tick.
+type Input uint8
+
+const (
+	InputLeft Input = 1 << iota
+	InputRight
+	InputUp
+	InputDown
+)
+
+const (
+	messageHello  = "hello"
+	messageReject = "reject"
+	messageLobby  = "lobby"
+	messageStart  = "start"
+	messageInput  = "input"
+	messageLeave  = "leave"
+)
+
+// message is sent as a line of JSON, which fields are used depends on the type.
+type message struct {
+	Type    string
+	Name    string   `json:",omitempty"`
+	Screen  string   `json:",omitempty"`
+	Error   string   `json:",omitempty"`
+	Players []string `json:",omitempty"`
+	Player  int
+	Tick    int
+	Input   Input
+	Seed    uint64
+	Speed   float64
+}
+
+type peer struct {
+	conn    net.Conn
+	encoder *json.Encoder
+	outbox  chan message
+	done    chan struct{}
+	once    sync.Once
+	player  int
+	// lastTick is the last tick this peer has sent input for
+	lastTick int
+}
+
+func newPeer(conn net.Conn, player int) *peer {
+	return &peer{
+		conn:     conn,
+		encoder:  json.NewEncoder(conn),
+		outbox:   make(chan message, outboxSize),
+		done:     make(chan struct{}),
+		player:   player,
+		lastTick: InputDelay - 1,
+	}
+}
+
+// start writes the messages of the outbox until the peer is closed.
+func (peer *peer) start() {
+	go func() {
+		for {
+			select {
+			case msg := <-peer.outbox:
+				if err := peer.write(msg); err != nil {
+					peer.close() // the reader drops the peer
+					return
+				}
+			case <-peer.done:
+				return
+			}
+		}
+	}()
+}
+
+// write sends the message right away, a peer that doesn't read it in time fails it.
+func (peer *peer) write(msg message) error {
+	if err := peer.conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return err
+	}
+	return peer.encoder.Encode(msg)
+}
+
+// send queues the message without waiting, so it can be called with the mutex of the session held.
+// A peer whose outbox is full has stopped reading and is closed.
+func (peer *peer) send(msg message) {
+	select {
+	case peer.outbox <- msg:
+	case <-peer.done:
+	default:
+		peer.close()
+	}
+}
+
+func (peer *peer) close() {
+	peer.once.Do(func() {
+		close(peer.done)
+		peer.conn.Close()
+	})
+}
+
+type tickInputs struct {
+	inputs   [MaxPlayers]Input
+	received [MaxPlayers]bool
+}
+
+// Session is one side of a LAN race. The host relays the input of every player to the others,
+// every side runs the same simulation from the same seed and only advances a tick once it has
+// the input of all players for it.
+type Session struct {
+	mutex    sync.Mutex
+	host     bool
+	screen   string
+	listener net.Listener
+	peers    []*peer
+	players  []string
+	local    int
+	started  bool
+	seed     uint64
+	speed    float64
+	inputs   map[int]*tickInputs
+	left     map[int]int // player -> first tick without input from the player
+	progress time.Time
+	err      error
+	closed   bool
+}
+
+// Host opens a lobby on the address. screen is the resolution of the game, which every player must share
+// because the simulation depends on it.
+func Host(address, name, screen string) (*Session, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %v", err)
+	}
+	session := newSession(true, screen)
+	session.listener = listener
+	session.players = []string{name}
+	go session.accept()
+	return session, nil
+}
+
+func Join(address, name, screen string) (*Session, error) {
+	conn, err := net.DialTimeout("tcp", address, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %v", err)
+	}
+	host := newPeer(conn, 0)
+	if err = host.write(message{Type: messageHello, Name: name, Screen: screen}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send hello: %v", err)
+	}
+	decoder := json.NewDecoder(conn)
+	var reply message
+	if err = decoder.Decode(&reply); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read lobby: %v", err)
+	}
+	if reply.Type == messageReject {
+		conn.Close()
+		return nil, errors.New(reply.Error)
+	}
+
+	session := newSession(false, screen)
+	session.peers = []*peer{host}
+	session.players = reply.Players
+	session.local = reply.Player
+	host.start()
+	go session.readHost(host, decoder)
+	return session, nil
+}
+
+func newSession(host bool, screen string) *Session {
+	return &Session{
+		host:     host,
+		screen:   screen,
+		inputs:   make(map[int]*tickInputs),
+		left:     make(map[int]int),
+		progress: time.Now(),
+	}
+}
+
+func (session *Session) accept() {
+	for {
+		conn, err := session.listener.Accept()
+		if err != nil {
+			return // the listener is closed
+		}
+		go session.handshake(conn)
+	}
+}
+
+func (session *Session) handshake(conn net.Conn) {
+	decoder := json.NewDecoder(conn)
+	var hello message
+	if err := decoder.Decode(&hello); err != nil || hello.Type != messageHello {
+		conn.Close()
+		return
+	}
+
+	session.mutex.Lock()
+	var reason string
+	switch {
+	case session.started:
+		reason = "the race has already started"
+	case len(session.players) >= MaxPlayers:
+		reason = "the lobby is full"
+	case hello.Screen != session.screen:
+		reason = fmt.Sprintf("the host plays in %s, switch to the same resolution", session.screen)
+	}
+	if reason != "" {
+		session.mutex.Unlock()
+		_ = newPeer(conn, 0).write(message{Type: messageReject, Error: reason})
+		conn.Close()
+		return
+	}
+	peer := newPeer(conn, len(session.players))
+	peer.start()
+	session.peers = append(session.peers, peer)
+	session.players = append(session.players, hello.Name)
+	session.broadcastLobby()
+	session.mutex.Unlock()
+
+	session.readClient(peer, decoder)
+}
+
+// broadcastLobby tells every client who is in the lobby, the mutex must be held.
+func (session *Session) broadcastLobby() {
+	for _, peer := range session.peers {
+		peer.send(message{Type: messageLobby, Players: session.players, Player: peer.player})
+	}
+}
+
+func (session *Session) readClient(peer *peer, decoder *json.Decoder) {
+	for {
+		var msg message
+		if err := decoder.Decode(&msg); err != nil {
+			session.dropPeer(peer)
+			return
+		}
+		if msg.Type != messageInput {
+			continue
+		}
+		session.mutex.Lock()
+		msg.Player = peer.player
+		session.storeInput(msg.Tick, msg.Player, msg.Input)
+		peer.lastTick = max(peer.lastTick, msg.Tick)
+		for _, other := range session.peers {
+			if other != peer {
+				other.send(msg)
+			}
+		}
+		session.mutex.Unlock()
+	}
+}
+
+// dropPeer removes a client that has disconnected. In the lobby the others move up,
+// in a race the player stops after the last tick the host has relayed for it.
+func (session *Session) dropPeer(peer *peer) {
+	peer.close()
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	index := -1
+	for i, other := range session.peers {
+		if other == peer {
+			index = i
+		}
+	}
+	if index < 0 {
+		return
+	}
+	session.peers = append(session.peers[:index], session.peers[index+1:]...)
+
+	if !session.started {
+		session.players = append(session.players[:peer.player], session.players[peer.player+1:]...)
+		for _, other := range session.peers {
+			if other.player > peer.player {
+				other.player--
+			}
+		}
+		session.broadcastLobby()
+		return
+	}
+	tick := peer.lastTick + 1
+	session.left[peer.player] = tick
+	for _, other := range session.peers {
+		other.send(message{Type: messageLeave, Player: peer.player, Tick: tick})
+	}
+}
+
+func (session *Session) readHost(host *peer, decoder *json.Decoder) {
+	for {
+		var msg message
+		if err := decoder.Decode(&msg); err != nil {
+			session.mutex.Lock()
+			if !session.closed {
+				session.err = errors.New("the connection to the host is lost")
+			}
+			session.mutex.Unlock()
+			host.close()
+			return
+		}
+		session.mutex.Lock()
+		switch msg.Type {
+		case messageLobby:
+			session.players = msg.Players
+			session.local = msg.Player
+		case messageStart:
+			session.players = msg.Players
+			session.seed = msg.Seed
+			session.speed = msg.Speed
+			session.started = true
+			session.progress = time.Now()
+		case messageInput:
+			session.storeInput(msg.Tick, msg.Player, msg.Input)
+		case messageLeave:
+			session.left[msg.Player] = msg.Tick
+		}
+		session.mutex.Unlock()
+	}
+}
+
+// storeInput keeps the input of a player, the mutex must be held.
+func (session *Session) storeInput(tick, player int, input Input) {
+	if player < 0 || player >= MaxPlayers {
+		return
+	}
+	inputs, ok := session.inputs[tick]
+	if !ok {
+		inputs = &tickInputs{}
+		session.inputs[tick] = inputs
+	}
+	inputs.inputs[player] = input
+	inputs.received[player] = true
+}
+
+// Start begins the race on every side, only the host can start it. speed is the steering speed of all cars.
+func (session *Session) Start(seed uint64, speed float64) error {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	if !session.host {
+		return errors.New("only the host can start the race")
+	}
+	if session.listener != nil {
+		session.listener.Close() // nobody can join a race that has started
+	}
+	session.started = true
+	session.seed = seed
+	session.speed = speed
+	session.progress = time.Now()
+	for _, peer := range session.peers {
+		peer.send(message{Type: messageStart, Players: session.players, Player: peer.player, Seed: seed, Speed: speed})
+	}
+	return nil
+}
+
+// Started returns the seed and the steering speed of the race once the host has started it.
+func (session *Session) Started() (uint64, float64, bool) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	return session.seed, session.speed, session.started
+}
+
+// SendInput schedules the local input of the current tick for InputDelay ticks later.
+func (session *Session) SendInput(tick int, input Input) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	tick += InputDelay
+	session.storeInput(tick, session.local, input)
+	msg := message{Type: messageInput, Player: session.local, Tick: tick, Input: input}
+	for _, peer := range session.peers {
+		peer.send(msg)
+	}
+}
+
+// Inputs returns the input of every player for the tick, it reports false while some of it hasn't arrived.
+func (session *Session) Inputs(tick int) ([]Input, bool) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	inputs := make([]Input, len(session.players))
+	if tick < InputDelay {
+		session.progress = time.Now()
+		return inputs, true
+	}
+	stored := session.inputs[tick]
+	for player := range inputs {
+		if leftAt, ok := session.left[player]; ok && tick >= leftAt {
+			continue
+		}
+		if stored == nil || !stored.received[player] {
+			return nil, false
+		}
+		inputs[player] = stored.inputs[player]
+	}
+	delete(session.inputs, tick)
+	session.progress = time.Now()
+	return inputs, true
+}
+
+// Stalled returns how long the race has been waiting for input.
+func (session *Session) Stalled() time.Duration {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	return time.Since(session.progress)
+}
+
+// DropLagging disconnects the clients the host is still waiting on for the tick.
+func (session *Session) DropLagging(tick int) {
+	session.mutex.Lock()
+	var lagging []*peer
+	stored := session.inputs[tick]
+	for _, peer := range session.peers {
+		if stored == nil || !stored.received[peer.player] {
+			lagging = append(lagging, peer)
+		}
+	}
+	session.mutex.Unlock()
+	for _, peer := range lagging {
+		peer.close() // the reader drops the peer
+	}
+}
+
+// Left reports whether the player has disconnected by the tick.
+func (session *Session) Left(player, tick int) bool {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	leftAt, ok := session.left[player]
+	return ok && tick >= leftAt
+}
+
+func (session *Session) Players() []string {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	return append([]string(nil), session.players...)
+}
+
+func (session *Session) LocalPlayer() int {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	return session.local
+}
+
+func (session *Session) IsHost() bool {
+	return session.host
+}
+
+// Addr returns the address the host listens on, e.g. to join it when it was opened on port 0.
+func (session *Session) Addr() net.Addr {
+	if session.listener == nil {
+		return nil
+	}
+	return session.listener.Addr()
+}
+
+// Err returns why the session can't go on, e.g. because the host has left.
+func (session *Session) Err() error {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	return session.err
+}
+
+func (session *Session) Close() {
+	session.mutex.Lock()
+	session.closed = true
+	peers := session.peers
+	session.mutex.Unlock()
+	if session.listener != nil {
+		session.listener.Close()
+	}
+	for _, peer := range peers {
+		peer.close()
+	}
+}
@@ -0,0 +1,109 @@
+package netplay
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+const screen = "1280x720"
+
+// eventually waits for the condition, the sessions talk to each other in goroutines.
+func eventually(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRaceOverLoopback(t *testing.T) {
+	host, err := Host("127.0.0.1:0", "Host", screen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer host.Close()
+	guest, err := Join(host.Addr().String(), "Guest", screen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer guest.Close()
+	leaver, err := Join(host.Addr().String(), "Leaver", screen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer leaver.Close()
+
+	want := []string{"Host", "Guest", "Leaver"}
+	eventually(t, "the lobby", func() bool {
+		return slices.Equal(host.Players(), want) && slices.Equal(guest.Players(), want) && slices.Equal(leaver.Players(), want)
+	})
+	if guest.LocalPlayer() != 1 || leaver.LocalPlayer() != 2 {
+		t.Fatalf("the players are %d and %d, want 1 and 2", guest.LocalPlayer(), leaver.LocalPlayer())
+	}
+
+	if err = guest.Start(1, 10); err == nil {
+		t.Fatal("a client has started the race")
+	}
+	if err = host.Start(42, 10); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the start", func() bool {
+		_, _, started := guest.Started()
+		return started
+	})
+	if seed, speed, _ := guest.Started(); seed != 42 || speed != 10 {
+		t.Fatalf("the race started with seed %d and speed %v, want 42 and 10", seed, speed)
+	}
+
+	// the leaver sends no input, so it stops at the first tick after the delay
+	leaver.Close()
+	eventually(t, "the leave on the host", func() bool { return host.Left(2, InputDelay) })
+	eventually(t, "the leave on the guest", func() bool { return guest.Left(2, InputDelay) })
+	if host.Left(2, InputDelay-1) {
+		t.Fatal("the leaver has left before its last input")
+	}
+
+	const ticks = InputDelay + 3
+	for tick := range ticks {
+		host.SendInput(tick, InputLeft)
+		guest.SendInput(tick, InputRight|InputUp)
+	}
+	for _, session := range []*Session{host, guest} {
+		for tick := range ticks + InputDelay {
+			var inputs []Input
+			eventually(t, "the inputs", func() bool {
+				var ok bool
+				inputs, ok = session.Inputs(tick)
+				return ok
+			})
+			want := []Input{InputLeft, InputRight | InputUp, 0}
+			if tick < InputDelay {
+				want = []Input{0, 0, 0}
+			}
+			if !slices.Equal(inputs, want) {
+				t.Fatalf("tick %d has inputs %v, want %v", tick, inputs, want)
+			}
+		}
+		if _, ok := session.Inputs(ticks + InputDelay); ok {
+			t.Fatal("there are inputs for a tick nobody has sent")
+		}
+	}
+}
+
+func TestJoinWithOtherScreen(t *testing.T) {
+	host, err := Host("127.0.0.1:0", "Host", screen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer host.Close()
+	if _, err = Join(host.Addr().String(), "Guest", "1920x1080"); err == nil {
+		t.Fatal("a player with another resolution has joined")
+	}
+	if players := host.Players(); len(players) != 1 {
+		t.Fatalf("the lobby has %v", players)
+	}
+}
@@ -0,0 +1,117 @@
+package pickups
+
+import (
+	"math/rand/v2"
+
+	"github.com/VxVxN/gamedevlib/rectangle"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	lanes           = 5
+	laneWidth       = 200
+	firstLaneCenter = 120
+	spawnY          = -200
+)
+
+type Kind int
+
+const (
+	FuelCan Kind = iota
+)
+
+type Pickup struct {
+	*rectangle.Rectangle
+	kind Kind
+}
+
+func (pickup *Pickup) Kind() Kind {
+	return pickup.kind
+}
+
+type Spawner struct {
+	screenHeight float64
+	startRoad    float64
+	images       map[Kind]*ebiten.Image
+	pickups      []*Pickup
+	rand         *rand.Rand
+	nextSpawn    float64
+	enabled      bool
+}
+
+func New(fuelCanImage *ebiten.Image, screenHeight, startRoad float64) *Spawner {
+	return &Spawner{
+		screenHeight: screenHeight,
+		startRoad:    startRoad,
+		images:       map[Kind]*ebiten.Image{FuelCan: fuelCanImage},
+		rand:         rand.New(rand.NewPCG(0, 0)),
+	}
+}
+
+func (spawner *Spawner) Reset(seed uint64) {
+	spawner.rand = rand.New(rand.NewPCG(seed, seed^0xf0e1))
+	spawner.pickups = spawner.pickups[:0]
+	spawner.nextSpawn = spawner.screenHeight
+}
+
+// SetEnabled turns random spawning on or off, pickups already on the road stay.
+func (spawner *Spawner) SetEnabled(enabled bool) {
+	spawner.enabled = enabled
+}
+
+func (spawner *Spawner) Update(scrollSpeed float64) {
+	pickups := spawner.pickups[:0]
+	for _, pickup := range spawner.pickups {
+		pickup.Y += scrollSpeed
+		if pickup.Y > spawner.screenHeight {
+			continue
+		}
+		pickups = append(pickups, pickup)
+	}
+	spawner.pickups = pickups
+
+	if !spawner.enabled {
+		return
+	}
+	spawner.nextSpawn -= scrollSpeed
+	if spawner.nextSpawn > 0 {
+		return
+	}
+	spawner.nextSpawn = 1500 + spawner.rand.Float64()*2500
+	spawner.Spawn(FuelCan, spawner.rand.IntN(lanes), spawnY)
+}
+
+// Spawn places a pickup into a lane at the given screen height.
+func (spawner *Spawner) Spawn(kind Kind, lane int, y float64) {
+	image := spawner.images[kind]
+	width, height := float64(image.Bounds().Dx())*0.6, float64(image.Bounds().Dy())*0.6
+	spawner.pickups = append(spawner.pickups, &Pickup{
+		Rectangle: rectangle.New(spawner.startRoad+firstLaneCenter+float64(lane*laneWidth)-width/2, y, width, height),
+		kind:      kind,
+	})
+}
+
+// Collect removes the pickups touched by the rectangle and returns them.
+func (spawner *Spawner) Collect(rectangle *rectangle.Rectangle) []*Pickup {
+	var collected []*Pickup
+	pickups := spawner.pickups[:0]
+	for _, pickup := range spawner.pickups {
+		if pickup.Collision(rectangle) {
+			collected = append(collected, pickup)
+			continue
+		}
+		pickups = append(pickups, pickup)
+	}
+	spawner.pickups = pickups
+	return collected
+}
+
+func (spawner *Spawner) Draw(screen *ebiten.Image) {
+	for _, pickup := range spawner.pickups {
+		image := spawner.images[pickup.kind]
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(pickup.Width/float64(image.Bounds().Dx()), pickup.Height/float64(image.Bounds().Dy()))
+		op.GeoM.Translate(pickup.X, pickup.Y)
+		screen.DrawImage(image, op)
+	}
+}
@@ -0,0 +1,252 @@
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/VxVxN/game/internal/settings"
+	"github.com/VxVxN/game/internal/shop"
+	"github.com/VxVxN/game/pkg/atomicfile"
+)
+
+const (
+	maxNameLength = 32
+	// DefaultID is the profile the records from before the profiles are moved to
+	DefaultID = "default"
+)
+
+// Profile is a player with their own records, campaign progress and settings.
+type Profile struct {
+	// ID names the directory of the profile, it stays the same when the profile is renamed
+	ID       string
+	Name     string
+	Settings settings.Overrides
+	Vehicle  string `json:",omitempty"` // the vehicle chosen in the garage, the default car if it is empty
+	Paint    string `json:",omitempty"`
+	// Purchases are the coins the profile has earned and what it has bought in the shop
+	Purchases shop.Purchases
+}
+
+// Profiles stores every profile and which one was played last.
+type Profiles struct {
+	path     string
+	dir      string
+	Active   string
+	Profiles []*Profile
+}
+
+// ErrCorrupt is returned by Load together with the profiles it could recover from a file that can't be read back.
+var ErrCorrupt = errors.New("the profiles file is corrupt")
+
+// Load reads the profiles from the file at path, the data of every profile is kept in a directory in dir.
+// A corrupt file is kept with the .corrupt extension and replaced by the profiles Read recovers.
+func Load(path, dir string) (*Profiles, error) {
+	profiles, err := Read(path, dir)
+	if profiles == nil || !errors.Is(err, ErrCorrupt) {
+		return profiles, err
+	}
+	if renameErr := os.Rename(path, path+".corrupt"); renameErr != nil {
+		return nil, fmt.Errorf("failed to move the corrupt file aside: %v", renameErr)
+	}
+	if saveErr := profiles.Save(); saveErr != nil {
+		return nil, errors.Join(err, saveErr)
+	}
+	return profiles, err
+}
+
+// Read reads the profiles like Load without writing anything. The profiles of a corrupt file come from
+// the backup, or from the directories if there is no good backup, so the records stay with their profiles.
+func Read(path, dir string) (*Profiles, error) {
+	profiles := &Profiles{path: path, dir: dir}
+	err := profiles.read(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return profiles, nil
+	}
+	if !errors.Is(err, ErrCorrupt) {
+		return profiles, err
+	}
+	if profiles.read(profiles.backupPath()) != nil {
+		if dirErr := profiles.readDirs(); dirErr != nil {
+			return nil, errors.Join(err, dirErr)
+		}
+	}
+	return profiles, err
+}
+
+// read replaces the profiles with the ones in the file.
+func (profiles *Profiles) read(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var read Profiles
+	if err = json.Unmarshal(data, &read); err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	profiles.Active = read.Active
+	profiles.Profiles = read.Profiles
+	return nil
+}
+
+// readDirs makes a profile named after every directory of profile data.
+func (profiles *Profiles) readDirs() error {
+	entries, err := os.ReadDir(profiles.dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read profile directories: %v", err)
+	}
+	profiles.Active = ""
+	profiles.Profiles = nil
+	for _, entry := range entries {
+		if entry.IsDir() {
+			profiles.Profiles = append(profiles.Profiles, &Profile{ID: entry.Name(), Name: entry.Name()})
+		}
+	}
+	return nil
+}
+
+func (profiles *Profiles) backupPath() string {
+	return atomicfile.BackupPath(profiles.path)
+}
+
+// Save writes every profile, Load falls back to the previous file if this one can't be read.
+func (profiles *Profiles) Save() error {
+	data, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteWithBackup(profiles.path, data)
+}
+
+func (profiles *Profiles) Get(id string) *Profile {
+	for _, profile := range profiles.Profiles {
+		if profile.ID == id {
+			return profile
+		}
+	}
+	return nil
+}
+
+// Find returns the profile with the ID, or else the one with the name.
+func (profiles *Profiles) Find(nameOrID string) *Profile {
+	if profile := profiles.Get(nameOrID); profile != nil {
+		return profile
+	}
+	for _, profile := range profiles.Profiles {
+		if strings.EqualFold(profile.Name, strings.TrimSpace(nameOrID)) {
+			return profile
+		}
+	}
+	return nil
+}
+
+// Current returns the profile played last, or the first one if it is gone.
+func (profiles *Profiles) Current() *Profile {
+	if profile := profiles.Get(profiles.Active); profile != nil {
+		return profile
+	}
+	if len(profiles.Profiles) == 0 {
+		return nil
+	}
+	return profiles.Profiles[0]
+}
+
+// Dir returns the directory with the records of the profile.
+func (profiles *Profiles) Dir(profile *Profile) string {
+	return filepath.Join(profiles.dir, profile.ID)
+}
+
+// Create adds a profile with its own directory, a new ID is made from the name.
+func (profiles *Profiles) Create(name string) (*Profile, error) {
+	return profiles.CreateWithID(makeID(name), name)
+}
+
+// CreateWithID adds a profile with the ID, or one like it if it is taken. A directory left without
+// a profile counts as taken, so a new profile doesn't get records that aren't its own.
+func (profiles *Profiles) CreateWithID(id, name string) (*Profile, error) {
+	name, err := profiles.checkName(name, nil)
+	if err != nil {
+		return nil, err
+	}
+	base := id
+	for i := 2; profiles.taken(id); i++ {
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+	profile := &Profile{ID: id, Name: name}
+	if err = os.MkdirAll(profiles.Dir(profile), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create profile directory: %v", err)
+	}
+	profiles.Profiles = append(profiles.Profiles, profile)
+	return profile, nil
+}
+
+func (profiles *Profiles) taken(id string) bool {
+	if profiles.Get(id) != nil {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(profiles.dir, id))
+	return !errors.Is(err, os.ErrNotExist)
+}
+
+func (profiles *Profiles) Rename(profile *Profile, name string) error {
+	name, err := profiles.checkName(name, profile)
+	if err != nil {
+		return err
+	}
+	profile.Name = name
+	return nil
+}
+
+// Delete removes the profile and its records, the last profile can't be deleted.
+func (profiles *Profiles) Delete(profile *Profile) error {
+	if len(profiles.Profiles) == 1 {
+		return errors.New("the last profile can't be deleted")
+	}
+	if err := os.RemoveAll(profiles.Dir(profile)); err != nil {
+		return fmt.Errorf("failed to remove profile directory: %v", err)
+	}
+	profiles.Profiles = slices.DeleteFunc(profiles.Profiles, func(other *Profile) bool {
+		return other == profile
+	})
+	if profiles.Active == profile.ID {
+		profiles.Active = profiles.Profiles[0].ID
+	}
+	return nil
+}
+
+// checkName returns the name without the spaces around it if no other profile than the renamed one has it.
+func (profiles *Profiles) checkName(name string, renamed *Profile) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("the name is empty")
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return "", fmt.Errorf("the name is longer than %d letters", maxNameLength)
+	}
+	for _, profile := range profiles.Profiles {
+		if profile != renamed && strings.EqualFold(profile.Name, name) {
+			return "", fmt.Errorf("there is a profile named %s already", profile.Name)
+		}
+	}
+	return name, nil
+}
+
+// makeID keeps the letters and digits of the name, so the directory can be found by it.
+func makeID(name string) string {
+	id := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+	if id == "" {
+		id = "profile"
+	}
+	return id
+}
@@ -0,0 +1,116 @@
+package scenery
+
+import (
+	"image/color"
+	"math/rand/v2"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+type Kind int
+
+const (
+	Tree Kind = iota
+	Building
+	Sign
+	Fence
+	KilometreMarker
+)
+
+var shadowColor = color.RGBA{A: 90}
+
+type object struct {
+	kind          Kind
+	x, y          float64
+	width, height float64
+	scale         float64
+	height3D      float64 // how tall the object is, scales the length of its shadow
+	image         *ebiten.Image
+	color         color.RGBA
+	label         string
+}
+
+func (object *object) draw(screen *ebiten.Image, face text.Face) {
+	switch object.kind {
+	case Tree:
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(object.scale, object.scale)
+		op.GeoM.Translate(object.x, object.y)
+		screen.DrawImage(object.image, op)
+	case Building:
+		drawRect(screen, object.x, object.y, object.width, object.height, object.color)
+		roof := color.RGBA{R: object.color.R / 4 * 3, G: object.color.G / 4 * 3, B: object.color.B / 4 * 3, A: 255}
+		drawRect(screen, object.x+8, object.y+8, object.width-16, object.height-16, roof)
+	case Sign:
+		drawRect(screen, object.x+object.width/2-3, object.y+object.height, 6, 14, color.RGBA{R: 90, G: 90, B: 90, A: 255})
+		drawRect(screen, object.x, object.y, object.width, object.height, object.color)
+		drawRect(screen, object.x+6, object.y+object.height/2-2, object.width-12, 4, color.White)
+	case Fence:
+		drawRect(screen, object.x, object.y, object.width, object.height, object.color)
+		for y := object.y; y < object.y+object.height; y += 40 {
+			drawRect(screen, object.x-3, y, object.width+6, 8, object.color)
+		}
+	case KilometreMarker:
+		drawRect(screen, object.x, object.y, object.width, object.height, object.color)
+		drawRect(screen, object.x, object.y, object.width, 8, color.RGBA{R: 200, G: 40, B: 40, A: 255})
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(object.x+object.width/2, object.y+12)
+		op.ColorScale.Scale(0, 0, 0, 1)
+		op.LayoutOptions.PrimaryAlign = text.AlignCenter
+		text.Draw(screen, object.label, face, op)
+	}
+}
+
+func (object *object) drawShadow(screen *ebiten.Image, shiftX, shiftY float64) {
+	shiftX *= object.height3D
+	shiftY *= object.height3D
+	switch object.kind {
+	case Tree:
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(object.scale, object.scale)
+		op.GeoM.Translate(object.x+shiftX, object.y+shiftY)
+		op.ColorScale.Scale(0, 0, 0, 0.35)
+		screen.DrawImage(object.image, op)
+	default:
+		drawRect(screen, object.x+shiftX, object.y+shiftY, object.width, object.height, shadowColor)
+	}
+}
+
+type layer struct {
+	speedFactor    float64
+	minGap, maxGap float64
+	kinds          []Kind
+	objects        []*object
+	nextSpawnY     float64
+}
+
+func newLayer(speedFactor, minGap, maxGap float64, kinds []Kind) *layer {
+	return &layer{
+		speedFactor: speedFactor,
+		minGap:      minGap,
+		maxGap:      maxGap,
+		kinds:       kinds,
+	}
+}
+
+func (layer *layer) update(speed, screenHeight float64) {
+	objects := layer.objects[:0]
+	for _, object := range layer.objects {
+		object.y += speed
+		if object.y > screenHeight {
+			continue
+		}
+		objects = append(objects, object)
+	}
+	layer.objects = objects
+}
+
+func (layer *layer) gap(random *rand.Rand) float64 {
+	return layer.minGap + random.Float64()*(layer.maxGap-layer.minGap)
+}
+
+func (layer *layer) reset() {
+	layer.objects = layer.objects[:0]
+	layer.nextSpawnY = 0
+}
@@ -0,0 +1,219 @@
+package scenery
+
+import (
+	"fmt"
+	"image/color"
+	"math/rand/v2"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/VxVxN/game/internal/shadow"
+	"github.com/VxVxN/game/pkg/background"
+)
+
+const (
+	roadMargin         = 28  // grey shoulder drawn inside the road image
+	spawnOffset        = 300 // the tallest building, so new objects appear fully above the screen
+	metresPerKilometre = 1000
+)
+
+type Scenery struct {
+	screenWidth, screenHeight float64
+	startRoad, roadWidth      float64
+	treeImages                []*ebiten.Image
+	markerFace                text.Face
+	layers                    []*layer
+	kinds                     map[Kind]bool
+	rand                      *rand.Rand
+	distance                  float64
+	nextMarker                int
+	sunDirection              shadow.DirectionShadow
+}
+
+func New(treeImages []*ebiten.Image, markerFace text.Face, screenWidth, screenHeight, startRoad, roadWidth float64) *Scenery {
+	scenery := &Scenery{
+		screenWidth:  screenWidth,
+		screenHeight: screenHeight,
+		startRoad:    startRoad,
+		roadWidth:    roadWidth,
+		treeImages:   treeImages,
+		markerFace:   markerFace,
+		kinds:        map[Kind]bool{Tree: true, Building: true, Sign: true, Fence: true},
+		rand:         rand.New(rand.NewPCG(0, 0)),
+	}
+	// In a top-down view taller objects are closer to the camera, so they scroll faster than the ground.
+	scenery.layers = []*layer{
+		newLayer(1.0, 150, 400, []Kind{Fence, Sign}),
+		newLayer(1.1, 60, 220, []Kind{Tree}),
+		newLayer(1.25, 250, 700, []Kind{Building}),
+	}
+	return scenery
+}
+
+func (scenery *Scenery) Reset(seed uint64) {
+	scenery.rand = rand.New(rand.NewPCG(seed, seed^0x5ce9e7))
+	scenery.distance = 0
+	scenery.nextMarker = 1
+	for _, layer := range scenery.layers {
+		layer.reset()
+		// pre-fill the screen so the run does not start on an empty roadside
+		for layer.nextSpawnY < scenery.screenHeight {
+			scenery.spawn(layer, layer.nextSpawnY)
+			layer.nextSpawnY += layer.gap(scenery.rand)
+		}
+		layer.nextSpawnY = 0
+	}
+}
+
+func (scenery *Scenery) Update(scrollSpeed float64) {
+	scenery.distance += scrollSpeed / background.PixelsPerMetre
+
+	for i, layer := range scenery.layers {
+		speed := scrollSpeed * layer.speedFactor
+		layer.update(speed, scenery.screenHeight)
+
+		layer.nextSpawnY -= speed
+		for layer.nextSpawnY <= 0 {
+			scenery.spawn(layer, layer.nextSpawnY-spawnOffset)
+			layer.nextSpawnY += layer.gap(scenery.rand)
+		}
+
+		// kilometre markers live on the ground layer so they stay in step with the road
+		if i == 0 && scenery.distance >= float64(scenery.nextMarker*metresPerKilometre) {
+			layer.objects = append(layer.objects, scenery.newMarker(scenery.nextMarker))
+			scenery.nextMarker++
+		}
+	}
+}
+
+func (scenery *Scenery) Draw(screen *ebiten.Image) {
+	if scenery.sunDirection != shadow.NotSun {
+		shiftX, shiftY := shadow.Offset(scenery.sunDirection)
+		for _, layer := range scenery.layers {
+			for _, object := range layer.objects {
+				object.drawShadow(screen, shiftX, shiftY)
+			}
+		}
+	}
+	for _, layer := range scenery.layers {
+		for _, object := range layer.objects {
+			object.draw(screen, scenery.markerFace)
+		}
+	}
+}
+
+func (scenery *Scenery) SetSunDirection(sunDirection shadow.DirectionShadow) {
+	scenery.sunDirection = sunDirection
+}
+
+// SetKinds limits the objects spawned from now on, objects already on screen stay until they scroll away.
+func (scenery *Scenery) SetKinds(kinds []Kind) {
+	scenery.kinds = make(map[Kind]bool, len(kinds))
+	for _, kind := range kinds {
+		scenery.kinds[kind] = true
+	}
+}
+
+func (scenery *Scenery) spawn(layer *layer, y float64) {
+	var kinds []Kind
+	for _, kind := range layer.kinds {
+		if scenery.kinds[kind] {
+			kinds = append(kinds, kind)
+		}
+	}
+	if len(kinds) == 0 {
+		return
+	}
+	kind := kinds[scenery.rand.IntN(len(kinds))]
+	left := scenery.rand.IntN(2) == 0
+	object := scenery.newObject(kind, left, y)
+	if object == nil {
+		return
+	}
+	layer.objects = append(layer.objects, object)
+}
+
+func (scenery *Scenery) newObject(kind Kind, left bool, y float64) *object {
+	var sideStart, sideEnd float64
+	if left {
+		sideStart, sideEnd = 0, scenery.startRoad+roadMargin
+	} else {
+		sideStart, sideEnd = scenery.startRoad+scenery.roadWidth-roadMargin, scenery.screenWidth
+	}
+	sideWidth := sideEnd - sideStart
+
+	object := &object{kind: kind, y: y}
+	switch kind {
+	case Tree:
+		object.image = scenery.treeImages[scenery.rand.IntN(len(scenery.treeImages))]
+		object.scale = 0.5 + scenery.rand.Float64()*0.5
+		object.width = float64(object.image.Bounds().Dx()) * object.scale
+		object.height = float64(object.image.Bounds().Dy()) * object.scale
+		object.height3D = 1
+	case Building:
+		object.width = 80 + scenery.rand.Float64()*100
+		object.height = 120 + scenery.rand.Float64()*180
+		object.color = buildingColors[scenery.rand.IntN(len(buildingColors))]
+		object.height3D = 1.5
+	case Sign:
+		object.width, object.height = 50, 30
+		object.color = color.RGBA{R: 40, G: 120, B: 60, A: 255}
+		object.height3D = 0.6
+	case Fence:
+		object.width = 8
+		object.height = 150 + scenery.rand.Float64()*250
+		object.color = color.RGBA{R: 130, G: 95, B: 60, A: 255}
+		object.height3D = 0.3
+	}
+	if object.width > sideWidth-10 {
+		if kind == Fence || kind == Sign {
+			return nil
+		}
+		// squeeze large objects onto narrow screens instead of overlapping the road
+		object.scale *= (sideWidth - 10) / object.width
+		object.height *= (sideWidth - 10) / object.width
+		object.width = sideWidth - 10
+	}
+	if object.width <= 0 {
+		return nil
+	}
+
+	switch kind {
+	case Fence, Sign:
+		// ground objects stand at the edge of the road
+		if left {
+			object.x = sideEnd - object.width - 6
+		} else {
+			object.x = sideStart + 6
+		}
+	default:
+		object.x = sideStart + 5 + scenery.rand.Float64()*(sideWidth-object.width-10)
+	}
+	return object
+}
+
+func (scenery *Scenery) newMarker(kilometre int) *object {
+	return &object{
+		kind:     KilometreMarker,
+		x:        scenery.startRoad + scenery.roadWidth - roadMargin + 6,
+		y:        -40,
+		width:    36,
+		height:   40,
+		color:    color.RGBA{R: 240, G: 240, B: 240, A: 255},
+		label:    fmt.Sprintf("%d", kilometre),
+		height3D: 0.4,
+	}
+}
+
+var buildingColors = []color.RGBA{
+	{R: 150, G: 90, B: 80, A: 255},
+	{R: 110, G: 110, B: 125, A: 255},
+	{R: 190, G: 170, B: 130, A: 255},
+	{R: 90, G: 100, B: 110, A: 255},
+}
+
+func drawRect(screen *ebiten.Image, x, y, width, height float64, clr color.Color) {
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(height), clr, false)
+}
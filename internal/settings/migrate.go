@@ -0,0 +1,45 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Version is the version of the schema of settings.json. A file of an older version is migrated when it is read,
+// it is written with the current version the next time the settings are saved.
+const Version = 1
+
+// migrations turn a settings.json of the version of their index into one of the next version.
+var migrations = []func(values map[string]json.RawMessage) error{
+	// the files from before the versions only lack the version
+	func(values map[string]json.RawMessage) error { return nil },
+}
+
+// migrate returns settings.json in the current schema. A file of a newer version is left as it is,
+// the fields this version doesn't know are ignored and WriteToFile doesn't write over it.
+func migrate(data []byte) ([]byte, error) {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	var version int
+	if raw, ok := values["Version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, fmt.Errorf("failed to read the version: %v", err)
+		}
+	}
+	if version < 0 {
+		return nil, fmt.Errorf("the version %d is negative", version)
+	}
+	if version >= Version {
+		return data, nil
+	}
+	for ; version < Version; version++ {
+		if err := migrations[version](values); err != nil {
+			return nil, fmt.Errorf("failed to migrate from version %d: %v", version, err)
+		}
+	}
+	values["Version"] = json.RawMessage(strconv.Itoa(Version))
+	return json.Marshal(values)
+}
@@ -2,23 +2,54 @@ package settings
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 
 	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/VxVxN/game/internal/input"
+	"github.com/VxVxN/game/pkg/atomicfile"
 )
 
 type Settings struct {
 	SavedSettings *settingValues
 	RawSettings   *settingValues
+	path          string
+	file          settingValues // what is in settings.json
+	values        settingValues // the settings of the profile without the session over them
+	overrides     *Overrides
+	session       Session
 	logger        *slog.Logger
 }
 
 type settingValues struct {
+	Version        int
 	Resolution     Resolution
 	MusicVolume    int
 	EffectsVolume  int
 	CarSensitivity float64
+	// LeaderboardURL is the address of the online leaderboard, it is off while empty
+	LeaderboardURL string
+	// PointerSteering lets the mouse or the touch screen steer the car
+	PointerSteering input.PointerMode
+	// Bindings are the controls of every profile, the actions missing from them keep their default bindings
+	Bindings input.Bindings
+}
+
+// Overrides are the settings a profile keeps for itself, the ones left nil come from settings.json.
+type Overrides struct {
+	MusicVolume    *int     `json:",omitempty"`
+	EffectsVolume  *int     `json:",omitempty"`
+	CarSensitivity *float64 `json:",omitempty"`
+}
+
+// Session are the settings given at launch, they are used until the game is closed but never saved.
+type Session struct {
+	Resolution Resolution // the saved resolution is used if it is empty
+	Windowed   bool       // a saved full screen becomes a window
+	Mute       bool
 }
 
 type Resolution string
@@ -44,38 +75,147 @@ func (resolution *Resolution) Size() (int, int) {
 	case Resolution1280x720:
 		return 1280, 720
 	}
+	var width, height int
+	if _, err := fmt.Sscanf(string(*resolution), "%dx%d", &width, &height); err == nil && width > 0 && height > 0 {
+		return width, height
+	}
 	return 1280, 720
 }
 
-func New(logger *slog.Logger) (*Settings, error) {
-	settings := settingValues{
-		Resolution:     ResolutionFullScreen,
-		MusicVolume:    100,
-		EffectsVolume:  100,
-		CarSensitivity: 10,
+// ParseResolution reads a window size like 1600x900.
+func ParseResolution(size string) (Resolution, error) {
+	var width, height int
+	if _, err := fmt.Sscanf(size, "%dx%d", &width, &height); err != nil || width <= 0 || height <= 0 {
+		return "", fmt.Errorf("%q isn't a size like 1280x720", size)
 	}
-	data, err := os.ReadFile("settings.json")
+	return Resolution(fmt.Sprintf("%dx%d", width, height)), nil
+}
+
+// New reads the settings from the file at path, the defaults are used until it is written. A file that
+// can't be read is moved aside with the .corrupt extension and the defaults are used instead.
+func New(logger *slog.Logger, path string) *Settings {
+	settings := defaultValues()
+	data, err := os.ReadFile(path)
 	if err == nil {
-		if err = json.Unmarshal(data, &settings); err != nil {
-			return nil, err
+		if err = decode(data, &settings); err != nil {
+			logger.Error("Failed to read the settings, using the defaults", "path", path, "error", err)
+			settings = defaultValues()
+			if err = os.Rename(path, path+".corrupt"); err != nil {
+				logger.Error("Failed to move the corrupt settings aside", "error", err)
+			}
 		}
 	}
+	settings.Bindings = settings.Bindings.WithDefaults()
 	logger.Info("Started settings", "data", string(data))
-	savedSettings := settings
-	rawSettings := settings
-	return &Settings{
-		SavedSettings: &savedSettings,
-		RawSettings:   &rawSettings,
-		logger:        logger,
-	}, nil
+	started := &Settings{
+		path:   path,
+		file:   settings,
+		logger: logger,
+	}
+	started.use(settings)
+	return started
+}
+
+func defaultValues() settingValues {
+	return settingValues{
+		Version:         Version,
+		Resolution:      ResolutionFullScreen,
+		MusicVolume:     100,
+		EffectsVolume:   100,
+		CarSensitivity:  10,
+		PointerSteering: input.PointerOff,
+	}
+}
+
+func decode(data []byte, settings *settingValues) error {
+	data, err := migrate(data)
+	if err != nil {
+		return fmt.Errorf("failed to migrate: %v", err)
+	}
+	return json.Unmarshal(data, settings)
+}
+
+// SetSession puts the settings given at launch over the saved ones.
+func (settings *Settings) SetSession(session Session) {
+	settings.session = session
+	settings.use(settings.values)
+}
+
+// use makes the values the settings, the ones of the session are put over them.
+func (settings *Settings) use(values settingValues) {
+	settings.values = values
+	if settings.session.Resolution != "" {
+		values.Resolution = settings.session.Resolution
+	}
+	if settings.session.Windowed && values.Resolution == ResolutionFullScreen {
+		values.Resolution = Resolution1280x720
+	}
+	if settings.session.Mute {
+		values.MusicVolume, values.EffectsVolume = 0, 0
+	}
+	savedSettings := values
+	rawSettings := values
+	settings.SavedSettings = &savedSettings
+	settings.RawSettings = &rawSettings
+}
+
+// withoutSession returns the values with the ones the session has set back to what they were before it,
+// the ones changed since then are kept.
+func (settings *Settings) withoutSession(values settingValues) settingValues {
+	if settings.session.Resolution != "" && values.Resolution == settings.session.Resolution ||
+		settings.session.Windowed && settings.values.Resolution == ResolutionFullScreen && values.Resolution == Resolution1280x720 {
+		values.Resolution = settings.values.Resolution
+	}
+	if settings.session.Mute {
+		if values.MusicVolume == 0 {
+			values.MusicVolume = settings.values.MusicVolume
+		}
+		if values.EffectsVolume == 0 {
+			values.EffectsVolume = settings.values.EffectsVolume
+		}
+	}
+	return values
+}
+
+// UseOverrides applies the settings of a profile, WriteToFile keeps the changes to them in the overrides.
+func (settings *Settings) UseOverrides(overrides *Overrides) {
+	settings.overrides = overrides
+	values := settings.file
+	if overrides.MusicVolume != nil {
+		values.MusicVolume = *overrides.MusicVolume
+	}
+	if overrides.EffectsVolume != nil {
+		values.EffectsVolume = *overrides.EffectsVolume
+	}
+	if overrides.CarSensitivity != nil {
+		values.CarSensitivity = *overrides.CarSensitivity
+	}
+	settings.use(values)
 }
 
+// WriteToFile saves the settings, the ones given at launch are left out. A file of a newer version
+// isn't written over, the fields this version doesn't know would be lost.
 func (settings *Settings) WriteToFile() error {
-	data, err := json.Marshal(settings.SavedSettings)
+	if settings.file.Version > Version {
+		return fmt.Errorf("%s is from a newer version of the game (schema %d), the changes are kept until the game is closed", filepath.Base(settings.path), settings.file.Version)
+	}
+	file := settings.withoutSession(*settings.SavedSettings)
+	settings.values = file
+	if settings.overrides != nil {
+		musicVolume, effectsVolume, carSensitivity := file.MusicVolume, file.EffectsVolume, file.CarSensitivity
+		settings.overrides.MusicVolume = &musicVolume
+		settings.overrides.EffectsVolume = &effectsVolume
+		settings.overrides.CarSensitivity = &carSensitivity
+		file.MusicVolume = settings.file.MusicVolume
+		file.EffectsVolume = settings.file.EffectsVolume
+		file.CarSensitivity = settings.file.CarSensitivity
+	}
+	settings.file = file
+	data, err := json.Marshal(file)
 	if err != nil {
 		return err
 	}
-	if err = os.WriteFile("settings.json", data, 0644); err != nil {
+	if err = atomicfile.Write(settings.path, data); err != nil {
 		return err
 	}
 	settings.logger.Info("Saved settings", "data", string(data))
@@ -88,3 +228,10 @@ func (settings *Settings) Save() {
 	settings.SavedSettings = &savedSettings
 	settings.RawSettings = &rawSettings
 }
+
+// SetBindings saves the controls, they are the same for every profile.
+func (settings *Settings) SetBindings(bindings input.Bindings) error {
+	settings.SavedSettings.Bindings = bindings
+	settings.RawSettings.Bindings = bindings
+	return settings.WriteToFile()
+}
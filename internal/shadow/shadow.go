@@ -27,8 +27,16 @@ func New(img *ebiten.Image, direction DirectionShadow) *Shadow {
 }
 
 func (shadow *Shadow) Draw(screen *ebiten.Image, x, y float64) {
+	shiftX, shiftY := Offset(shadow.DirectionShadow)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(x+shiftX, y+shiftY)
+	screen.DrawImage(shadow.Image, op)
+}
+
+func Offset(direction DirectionShadow) (float64, float64) {
 	var shiftX, shiftY float64
-	switch shadow.DirectionShadow {
+	switch direction {
 	case SunStraight:
 		shiftY = 40
 	case SunLeft:
@@ -42,10 +50,7 @@ func (shadow *Shadow) Draw(screen *ebiten.Image, x, y float64) {
 		shiftX = -40
 		shiftY = 20
 	}
-
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(x+shiftX, y+shiftY)
-	screen.DrawImage(shadow.Image, op)
+	return shiftX, shiftY
 }
 
 func (shadow *Shadow) SetDirection(direction DirectionShadow) {
@@ -0,0 +1,164 @@
+package shop
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/VxVxN/game/pkg/player"
+)
+
+const (
+	coinDistance  = 50 // metres driven for a coin
+	nearMissCoins = 2
+	// DefaultPaint is the colour every car comes in
+	DefaultPaint = "factory"
+)
+
+var ErrNotEnoughCoins = errors.New("not enough coins")
+
+// Coins returns what a run pays out for the distance in metres and the near misses.
+func Coins(distance float64, nearMisses int) int {
+	return int(distance/coinDistance) + nearMisses*nearMissCoins
+}
+
+// Paint recolours a car by tinting its sprite.
+type Paint struct {
+	ID    string
+	Name  string
+	Tint  [3]float32 // red, green and blue scale
+	Price int
+}
+
+// Paints are the colours in the order they are shown.
+var Paints = []Paint{
+	{ID: DefaultPaint, Name: "Factory", Tint: [3]float32{1, 1, 1}},
+	{ID: "midnight", Name: "Midnight blue", Tint: [3]float32{0.5, 0.7, 1.5}, Price: 150},
+	{ID: "lime", Name: "Lime", Tint: [3]float32{0.7, 1.4, 0.5}, Price: 150},
+	{ID: "gold", Name: "Gold", Tint: [3]float32{1.4, 1.2, 0.4}, Price: 300},
+	{ID: "shadow", Name: "Shadow", Tint: [3]float32{0.45, 0.45, 0.5}, Price: 500},
+}
+
+// FindPaint returns the paint with the ID, the factory paint if there is none.
+func FindPaint(id string) Paint {
+	for _, paint := range Paints {
+		if paint.ID == id {
+			return paint
+		}
+	}
+	return Paints[0]
+}
+
+// Upgrade improves the handling of every car of the profile, a level at a time.
+type Upgrade struct {
+	ID          string
+	Name        string
+	Description string
+	Prices      []int // of every level
+	apply       func(handling *player.Handling, level int)
+}
+
+// Upgrades are the upgrades in the order they are shown.
+var Upgrades = []Upgrade{
+	{ID: "steering", Name: "Steering response", Description: "+10% steering a level", Prices: []int{150, 300, 600},
+		apply: func(handling *player.Handling, level int) {
+			handling.Steering *= 1 + 0.1*float64(level)
+		}},
+	{ID: "brakes", Name: "Brakes", Description: "+20% braking a level", Prices: []int{150, 300, 600},
+		apply: func(handling *player.Handling, level int) {
+			handling.Brakes *= 1 + 0.2*float64(level)
+		}},
+	{ID: "armour", Name: "Armour", Description: "Survive one more crash a run", Prices: []int{500, 1000},
+		apply: func(handling *player.Handling, level int) {
+			handling.Armour += level
+		}},
+}
+
+// Apply returns the handling with the upgrades at the levels, the ones that aren't known are left out.
+func Apply(handling player.Handling, levels map[string]int) player.Handling {
+	for _, upgrade := range Upgrades {
+		if level := min(levels[upgrade.ID], len(upgrade.Prices)); level > 0 {
+			upgrade.apply(&handling, level)
+		}
+	}
+	return handling
+}
+
+// Purchases are the coins a profile has earned and what it has bought with them.
+type Purchases struct {
+	Coins    int
+	Vehicles []string       `json:",omitempty"`
+	Paints   []string       `json:",omitempty"`
+	Upgrades map[string]int `json:",omitempty"` // the level of every upgrade bought
+}
+
+func (purchases *Purchases) Earn(coins int) {
+	purchases.Coins += coins
+}
+
+func (purchases *Purchases) pay(price int) error {
+	if purchases.Coins < price {
+		return ErrNotEnoughCoins
+	}
+	purchases.Coins -= price
+	return nil
+}
+
+// OwnsVehicle reports whether the vehicle can be driven, the ones without a price come with the profile.
+func (purchases *Purchases) OwnsVehicle(id string, price int) bool {
+	return price == 0 || slices.Contains(purchases.Vehicles, id)
+}
+
+func (purchases *Purchases) BuyVehicle(id string, price int) error {
+	if purchases.OwnsVehicle(id, price) {
+		return fmt.Errorf("the vehicle is in the garage already")
+	}
+	if err := purchases.pay(price); err != nil {
+		return err
+	}
+	purchases.Vehicles = append(purchases.Vehicles, id)
+	return nil
+}
+
+func (purchases *Purchases) OwnsPaint(paint Paint) bool {
+	return paint.Price == 0 || slices.Contains(purchases.Paints, paint.ID)
+}
+
+func (purchases *Purchases) BuyPaint(paint Paint) error {
+	if purchases.OwnsPaint(paint) {
+		return fmt.Errorf("%s is bought already", paint.Name)
+	}
+	if err := purchases.pay(paint.Price); err != nil {
+		return err
+	}
+	purchases.Paints = append(purchases.Paints, paint.ID)
+	return nil
+}
+
+func (purchases *Purchases) Level(upgrade Upgrade) int {
+	return purchases.Upgrades[upgrade.ID]
+}
+
+// NextPrice returns the price of the next level of the upgrade, false if it is at the top level.
+func (purchases *Purchases) NextPrice(upgrade Upgrade) (int, bool) {
+	level := purchases.Level(upgrade)
+	if level >= len(upgrade.Prices) {
+		return 0, false
+	}
+	return upgrade.Prices[level], true
+}
+
+func (purchases *Purchases) BuyUpgrade(upgrade Upgrade) error {
+	price, ok := purchases.NextPrice(upgrade)
+	if !ok {
+		return fmt.Errorf("%s is at the top level", upgrade.Name)
+	}
+	if err := purchases.pay(price); err != nil {
+		return err
+	}
+	if purchases.Upgrades == nil {
+		purchases.Upgrades = make(map[string]int)
+	}
+	purchases.Upgrades[upgrade.ID]++
+	return nil
+}
@@ -0,0 +1,38 @@
+package siren
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+const (
+	lowFrequency  = 650
+	highFrequency = 1250
+	cycleSeconds  = 2 // one rise and fall of the wail
+)
+
+// New returns a looping police siren synthesised as a sine wave sweeping up and down.
+func New(audioContext *audio.Context) (*audio.Player, error) {
+	sampleRate := audioContext.SampleRate()
+	samples := sampleRate * cycleSeconds
+
+	// 16-bit little endian stereo
+	pcm := make([]byte, samples*4)
+	var phase float64
+	for i := 0; i < samples; i++ {
+		progress := float64(i) / float64(samples)
+		sweep := (1 - math.Cos(2*math.Pi*progress)) / 2
+		frequency := lowFrequency + (highFrequency-lowFrequency)*sweep
+		phase += 2 * math.Pi * frequency / float64(sampleRate)
+
+		sample := int16(math.Sin(phase) * 0.3 * math.MaxInt16)
+		binary.LittleEndian.PutUint16(pcm[i*4:], uint16(sample))
+		binary.LittleEndian.PutUint16(pcm[i*4+2:], uint16(sample))
+	}
+
+	loop := audio.NewInfiniteLoop(bytes.NewReader(pcm), int64(len(pcm)))
+	return audioContext.NewPlayer(loop)
+}
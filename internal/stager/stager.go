@@ -14,8 +14,19 @@ const (
 	MainMenuStage
 	MenuStage
 	StatisticsStage
-	SetPlayerRecordStage
+	ProfileNameStage
 	SettingsStage
+	ModeSelectStage
+	LevelSelectStage
+	EditorStage
+	LobbyStage
+	RecoverRecordsStage
+	DashboardStage
+	ProfilesStage
+	AchievementsStage
+	GarageStage
+	ShopStage
+	ControlsStage
 )
 
 func (stage Stage) String() string {
@@ -30,10 +41,32 @@ func (stage Stage) String() string {
 		return "MenuStage"
 	case StatisticsStage:
 		return "StatisticsStage"
-	case SetPlayerRecordStage:
-		return "SetPlayerRecordStage"
+	case ProfileNameStage:
+		return "ProfileNameStage"
 	case SettingsStage:
 		return "SettingsStage"
+	case ModeSelectStage:
+		return "ModeSelectStage"
+	case LevelSelectStage:
+		return "LevelSelectStage"
+	case EditorStage:
+		return "EditorStage"
+	case LobbyStage:
+		return "LobbyStage"
+	case RecoverRecordsStage:
+		return "RecoverRecordsStage"
+	case DashboardStage:
+		return "DashboardStage"
+	case ProfilesStage:
+		return "ProfilesStage"
+	case AchievementsStage:
+		return "AchievementsStage"
+	case GarageStage:
+		return "GarageStage"
+	case ShopStage:
+		return "ShopStage"
+	case ControlsStage:
+		return "ControlsStage"
 	}
 	return ""
 }
@@ -0,0 +1,83 @@
+package traffic
+
+import "sort"
+
+// Runner hands out the waves and items of a script as the player drives past their distance.
+type Runner struct {
+	waves      []*Wave
+	hazards    []*Item
+	pickups    []*Item
+	nextWave   int
+	nextHazard int
+	nextPickup int
+}
+
+// Batch is everything that enters the road in one tick.
+type Batch struct {
+	Waves   []*Wave
+	Hazards []*Item
+	Pickups []*Item
+}
+
+func NewRunner(script *Script) *Runner {
+	runner := &Runner{}
+	if script == nil {
+		return runner
+	}
+	runner.waves = append(runner.waves, script.Waves...)
+	sort.SliceStable(runner.waves, func(i, j int) bool {
+		return runner.waves[i].At < runner.waves[j].At
+	})
+	runner.hazards = sortedItems(script.Hazards)
+	runner.pickups = sortedItems(script.Pickups)
+	return runner
+}
+
+func sortedItems(items []*Item) []*Item {
+	sorted := append([]*Item(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].At < sorted[j].At
+	})
+	return sorted
+}
+
+// Skip drops everything before the distance, e.g. when a run starts from the middle of a level.
+func (runner *Runner) Skip(distance float64) {
+	for runner.nextWave < len(runner.waves) && runner.waves[runner.nextWave].At < distance {
+		runner.nextWave++
+	}
+	for runner.nextHazard < len(runner.hazards) && runner.hazards[runner.nextHazard].At < distance {
+		runner.nextHazard++
+	}
+	for runner.nextPickup < len(runner.pickups) && runner.pickups[runner.nextPickup].At < distance {
+		runner.nextPickup++
+	}
+}
+
+// Due returns what starts at or before the distance and has not been returned yet.
+func (runner *Runner) Due(distance float64) Batch {
+	var batch Batch
+	start := runner.nextWave
+	for runner.nextWave < len(runner.waves) && runner.waves[runner.nextWave].At <= distance {
+		runner.nextWave++
+	}
+	batch.Waves = runner.waves[start:runner.nextWave]
+
+	start = runner.nextHazard
+	for runner.nextHazard < len(runner.hazards) && runner.hazards[runner.nextHazard].At <= distance {
+		runner.nextHazard++
+	}
+	batch.Hazards = runner.hazards[start:runner.nextHazard]
+
+	start = runner.nextPickup
+	for runner.nextPickup < len(runner.pickups) && runner.pickups[runner.nextPickup].At <= distance {
+		runner.nextPickup++
+	}
+	batch.Pickups = runner.pickups[start:runner.nextPickup]
+	return batch
+}
+
+// Active reports whether anything is still to come.
+func (runner *Runner) Active() bool {
+	return runner.nextWave < len(runner.waves) || runner.nextHazard < len(runner.hazards) || runner.nextPickup < len(runner.pickups)
+}
@@ -0,0 +1,410 @@
+package traffic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/VxVxN/game/internal/cargenerator"
+	"github.com/VxVxN/game/internal/hazards"
+	"github.com/VxVxN/game/internal/pickups"
+	"github.com/VxVxN/game/pkg/atomicfile"
+)
+
+const (
+	lanes          = 5
+	defaultSpacing = 400 // pixels between the vehicles of a slalom or a convoy
+	// carLength is how many metres apart lanes closed one after another have to be for a car to drive between them
+	carLength = 20
+)
+
+type Pattern string
+
+const (
+	// PatternWall puts one vehicle into each of the lanes side by side.
+	PatternWall Pattern = "wall"
+	// PatternSlalom puts Count vehicles one after another, alternating between the lanes.
+	PatternSlalom Pattern = "slalom"
+	// PatternConvoy puts Count vehicles one after another into the first lane.
+	PatternConvoy Pattern = "convoy"
+)
+
+type Vehicle string
+
+const (
+	VehicleCar       Vehicle = "car"
+	VehicleTruck     Vehicle = "truck"
+	VehicleLongTruck Vehicle = "long_truck"
+)
+
+func (vehicle Vehicle) Kind() cargenerator.VehicleKind {
+	switch vehicle {
+	case VehicleTruck:
+		return cargenerator.TruckKind
+	case VehicleLongTruck:
+		return cargenerator.LongTruckKind
+	}
+	return cargenerator.CarKind
+}
+
+// Wave is a group of vehicles entering the road at the top of the screen once the player has driven At metres.
+type Wave struct {
+	At      float64
+	Pattern Pattern
+	Vehicle Vehicle
+	Lanes   []int   // numbered from 1 like on the road signs
+	Count   int     `json:",omitempty"`
+	Spacing float64 `json:",omitempty"`
+
+	line int
+}
+
+// Placement is a single vehicle of a wave, Offset is how far above the top of the screen it starts.
+type Placement struct {
+	Kind   cargenerator.VehicleKind
+	Lane   int // numbered from 0
+	Offset float64
+}
+
+func (wave *Wave) Line() int {
+	return wave.line
+}
+
+func (wave *Wave) Placements() []Placement {
+	spacing := wave.Spacing
+	if spacing <= 0 {
+		spacing = defaultSpacing
+	}
+	var placements []Placement
+	switch wave.Pattern {
+	case PatternWall:
+		for _, lane := range wave.Lanes {
+			placements = append(placements, Placement{Kind: wave.Vehicle.Kind(), Lane: lane - 1})
+		}
+	case PatternSlalom:
+		for i := 0; i < wave.Count; i++ {
+			lane := wave.Lanes[i%len(wave.Lanes)]
+			placements = append(placements, Placement{Kind: wave.Vehicle.Kind(), Lane: lane - 1, Offset: float64(i) * spacing})
+		}
+	case PatternConvoy:
+		for i := 0; i < wave.Count; i++ {
+			placements = append(placements, Placement{Kind: wave.Vehicle.Kind(), Lane: wave.Lanes[0] - 1, Offset: float64(i) * spacing})
+		}
+	}
+	return placements
+}
+
+// Item is a single hazard or pickup placed into a lane, it appears at the top of the screen at At metres.
+type Item struct {
+	At   float64
+	Lane int // numbered from 1 like the lanes of a wave
+	Kind string
+
+	line int
+}
+
+var hazardKinds = map[string]hazards.Kind{
+	"cone":      hazards.Cone,
+	"barrier":   hazards.Barrier,
+	"pothole":   hazards.Pothole,
+	"oil_slick": hazards.OilSlick,
+	"debris":    hazards.Debris,
+}
+
+var pickupKinds = map[string]pickups.Kind{
+	"fuel_can": pickups.FuelCan,
+}
+
+func (item *Item) HazardKind() hazards.Kind {
+	return hazardKinds[item.Kind]
+}
+
+func (item *Item) PickupKind() pickups.Kind {
+	return pickupKinds[item.Kind]
+}
+
+type Script struct {
+	Waves   []*Wave
+	Hazards []*Item `json:",omitempty"`
+	Pickups []*Item `json:",omitempty"`
+}
+
+// ScriptError is a problem found in a script file, Line is 1-based.
+type ScriptError struct {
+	Line    int
+	Message string
+}
+
+func (err *ScriptError) Error() string {
+	return fmt.Sprintf("line %d: %s", err.Line, err.Message)
+}
+
+func Load(path string) (*Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read traffic script: %v", err)
+	}
+	script, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid traffic script %s: %w", path, err)
+	}
+	return script, nil
+}
+
+// Parse decodes and validates a script, all problems are reported together with their line numbers.
+func Parse(data []byte) (*Script, error) {
+	script, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if err = script.Validate(); err != nil {
+		return nil, err
+	}
+	return script, nil
+}
+
+func (script *Script) Validate() error {
+	var errs []error
+	for _, wave := range script.Waves {
+		for _, message := range wave.validate() {
+			errs = append(errs, &ScriptError{Line: wave.line, Message: message})
+		}
+	}
+	for _, item := range script.Hazards {
+		if _, ok := hazardKinds[item.Kind]; !ok {
+			errs = append(errs, &ScriptError{Line: item.line, Message: fmt.Sprintf("unknown hazard %q, expected cone, barrier, pothole, oil_slick or debris", item.Kind)})
+		}
+		errs = append(errs, item.validate()...)
+	}
+	for _, item := range script.Pickups {
+		if _, ok := pickupKinds[item.Kind]; !ok {
+			errs = append(errs, &ScriptError{Line: item.line, Message: fmt.Sprintf("unknown pickup %q, expected fuel_can", item.Kind)})
+		}
+		errs = append(errs, item.validate()...)
+	}
+	errs = append(errs, script.validateLanes()...)
+	return errors.Join(errs...)
+}
+
+// closure is a wall or a lethal hazard, the lanes it closes are numbered from 1.
+type closure struct {
+	at    float64
+	lanes []int
+	line  int
+}
+
+// validateLanes reports the walls and lethal hazards that close every lane together with the ones
+// less than a car length away, there would be no way past them.
+func (script *Script) validateLanes() []error {
+	var closures []closure
+	for _, wave := range script.Waves {
+		if wave.Pattern == PatternWall {
+			closures = append(closures, closure{at: wave.At, lanes: wave.Lanes, line: wave.line})
+		}
+	}
+	for _, item := range script.Hazards {
+		if kind, ok := hazardKinds[item.Kind]; ok && kind.Lethal() {
+			closures = append(closures, closure{at: item.At, lanes: []int{item.Lane}, line: item.line})
+		}
+	}
+	var errs []error
+	for _, current := range closures {
+		var closed [lanes]bool
+		for _, other := range closures {
+			if math.Abs(other.at-current.at) >= carLength {
+				continue
+			}
+			for _, lane := range other.lanes {
+				if lane >= 1 && lane <= lanes {
+					closed[lane-1] = true
+				}
+			}
+		}
+		if !slices.Contains(closed[:], false) {
+			errs = append(errs, &ScriptError{Line: current.line, Message: fmt.Sprintf("every lane is closed within %d m of At %v, leave one open", carLength, current.at)})
+		}
+	}
+	return errs
+}
+
+// Clone returns a copy that can be changed without changing the script.
+func (script *Script) Clone() *Script {
+	clone := &Script{
+		Waves:   make([]*Wave, len(script.Waves)),
+		Hazards: cloneItems(script.Hazards),
+		Pickups: cloneItems(script.Pickups),
+	}
+	for i, wave := range script.Waves {
+		waveClone := *wave
+		waveClone.Lanes = slices.Clone(wave.Lanes)
+		clone.Waves[i] = &waveClone
+	}
+	return clone
+}
+
+func cloneItems(items []*Item) []*Item {
+	if items == nil {
+		return nil
+	}
+	clones := make([]*Item, len(items))
+	for i, item := range items {
+		itemClone := *item
+		clones[i] = &itemClone
+	}
+	return clones
+}
+
+func (script *Script) Save(path string) error {
+	data, err := json.MarshalIndent(script, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(path, data)
+}
+
+// decode walks the tokens by hand so every wave and item remembers the line it starts on.
+func decode(data []byte) (*Script, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	script := &Script{}
+
+	if err := expectDelim(decoder, data, '{'); err != nil {
+		return nil, err
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, syntaxError(data, decoder, err)
+		}
+		key, _ := token.(string)
+		switch key {
+		case "Waves":
+			script.Waves, err = decodeList(decoder, data, func(line int) *Wave { return &Wave{line: line} })
+		case "Hazards":
+			script.Hazards, err = decodeList(decoder, data, func(line int) *Item { return &Item{line: line} })
+		case "Pickups":
+			script.Pickups, err = decodeList(decoder, data, func(line int) *Item { return &Item{line: line} })
+		default:
+			err = &ScriptError{Line: lineAt(data, decoder.InputOffset()), Message: fmt.Sprintf("unknown field %q", key)}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := expectDelim(decoder, data, '}'); err != nil {
+		return nil, err
+	}
+	return script, nil
+}
+
+func decodeList[T any](decoder *json.Decoder, data []byte, newElement func(line int) *T) ([]*T, error) {
+	if err := expectDelim(decoder, data, '['); err != nil {
+		return nil, err
+	}
+	var list []*T
+	for decoder.More() {
+		line := lineAt(data, nextValueOffset(data, decoder.InputOffset()))
+		element := newElement(line)
+		if err := decoder.Decode(element); err != nil {
+			var syntax *json.SyntaxError
+			if errors.As(err, &syntax) {
+				return nil, syntaxError(data, decoder, err)
+			}
+			return nil, &ScriptError{Line: line, Message: err.Error()}
+		}
+		list = append(list, element)
+	}
+	if err := expectDelim(decoder, data, ']'); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (item *Item) validate() []error {
+	var errs []error
+	if item.At < 0 {
+		errs = append(errs, &ScriptError{Line: item.line, Message: "At must not be negative"})
+	}
+	if item.Lane < 1 || item.Lane > lanes {
+		errs = append(errs, &ScriptError{Line: item.line, Message: fmt.Sprintf("lane %d is out of range 1-%d", item.Lane, lanes)})
+	}
+	return errs
+}
+
+func (wave *Wave) validate() []string {
+	var messages []string
+	if wave.At < 0 {
+		messages = append(messages, "At must not be negative")
+	}
+	switch wave.Vehicle {
+	case VehicleCar, VehicleTruck, VehicleLongTruck:
+	default:
+		messages = append(messages, fmt.Sprintf("unknown vehicle %q, expected car, truck or long_truck", wave.Vehicle))
+	}
+	if len(wave.Lanes) == 0 {
+		messages = append(messages, "at least one lane is required")
+	}
+	for i, lane := range wave.Lanes {
+		if lane < 1 || lane > lanes {
+			messages = append(messages, fmt.Sprintf("lane %d is out of range 1-%d", lane, lanes))
+		}
+		if slices.Contains(wave.Lanes[:i], lane) {
+			messages = append(messages, fmt.Sprintf("lane %d is given twice", lane))
+		}
+	}
+	switch wave.Pattern {
+	case PatternWall:
+		if len(wave.Lanes) >= lanes {
+			messages = append(messages, "a wall must leave at least one lane open")
+		}
+	case PatternSlalom, PatternConvoy:
+		if wave.Count <= 0 {
+			messages = append(messages, fmt.Sprintf("%s needs a positive Count", wave.Pattern))
+		}
+	default:
+		messages = append(messages, fmt.Sprintf("unknown pattern %q, expected wall, slalom or convoy", wave.Pattern))
+	}
+	return messages
+}
+
+func expectDelim(decoder *json.Decoder, data []byte, expected json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		if err == io.EOF {
+			return &ScriptError{Line: lineAt(data, int64(len(data))), Message: fmt.Sprintf("unexpected end of file, expected %q", expected)}
+		}
+		return syntaxError(data, decoder, err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != expected {
+		return &ScriptError{Line: lineAt(data, decoder.InputOffset()), Message: fmt.Sprintf("expected %q, got %v", expected, token)}
+	}
+	return nil
+}
+
+func syntaxError(data []byte, decoder *json.Decoder, err error) error {
+	offset := decoder.InputOffset()
+	var syntax *json.SyntaxError
+	if errors.As(err, &syntax) {
+		offset = syntax.Offset
+	}
+	return &ScriptError{Line: lineAt(data, offset), Message: err.Error()}
+}
+
+// nextValueOffset skips the separators in front of the next array element.
+func nextValueOffset(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) && strings.ContainsRune(" \t\r\n,", rune(data[offset])) {
+		offset++
+	}
+	return offset
+}
+
+func lineAt(data []byte, offset int64) int {
+	offset = min(offset, int64(len(data)))
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
@@ -0,0 +1,189 @@
+package traffic
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// errorLines returns the lines of every ScriptError in the error.
+func errorLines(err error) []int {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var lines []int
+		for _, err := range joined.Unwrap() {
+			lines = append(lines, errorLines(err)...)
+		}
+		return lines
+	}
+	var scriptErr *ScriptError
+	if errors.As(err, &scriptErr) {
+		return []int{scriptErr.Line}
+	}
+	return nil
+}
+
+func TestParse(t *testing.T) {
+	script, err := Parse([]byte(`{
+  "Waves": [
+    {"At": 100, "Pattern": "wall", "Vehicle": "car", "Lanes": [1, 2, 3]},
+    {
+      "At": 300,
+      "Pattern": "slalom",
+      "Vehicle": "truck",
+      "Lanes": [2, 4],
+      "Count": 4
+    }
+  ],
+  "Hazards": [{"At": 200, "Lane": 5, "Kind": "barrier"}],
+  "Pickups": [{"At": 250, "Lane": 1, "Kind": "fuel_can"}]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(script.Waves) != 2 || len(script.Hazards) != 1 || len(script.Pickups) != 1 {
+		t.Fatalf("the script is %+v", script)
+	}
+	if line := script.Waves[0].Line(); line != 3 {
+		t.Fatalf("the first wave starts on line %d, want 3", line)
+	}
+	if line := script.Waves[1].Line(); line != 4 {
+		t.Fatalf("the second wave starts on line %d, want 4", line)
+	}
+	if placements := script.Waves[1].Placements(); len(placements) != 4 || placements[1].Lane != 3 || placements[2].Lane != 1 {
+		t.Fatalf("the slalom is %+v", placements)
+	}
+}
+
+func TestParseErrorLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		lines  []int
+	}{
+		{"syntax error", `{
+  "Waves": [
+    {"At": 100, "Pattern": "wall" "Vehicle": "car", "Lanes": [1]}
+  ]
+}`, []int{3}},
+		{"unknown field", `{
+  "Waves": [],
+  "Trains": []
+}`, []int{3}},
+		{"unknown field in a wave", `{
+  "Waves": [
+    {"At": 100, "Pattern": "wall", "Vehicle": "car", "Lanes": [1]},
+    {"At": 200, "Pattern": "wall", "Vehicle": "car", "Lane": 1}
+  ]
+}`, []int{4}},
+		{"wrong type", `{
+  "Waves": [
+    {"At": "soon", "Pattern": "wall", "Vehicle": "car", "Lanes": [1]}
+  ]
+}`, []int{3}},
+		{"end of file", `{
+  "Waves": [`, []int{2}},
+		{"every problem", `{
+  "Waves": [
+    {"At": -1, "Pattern": "wall", "Vehicle": "car", "Lanes": [1]},
+    {"At": 100, "Pattern": "zigzag", "Vehicle": "car", "Lanes": [1]},
+    {"At": 200, "Pattern": "wall", "Vehicle": "bus", "Lanes": [6]},
+    {"At": 300, "Pattern": "convoy", "Vehicle": "car", "Lanes": [1]}
+  ],
+  "Hazards": [
+    {"At": 100, "Lane": 1, "Kind": "lava"}
+  ],
+  "Pickups": [
+    {"At": 100, "Lane": 0, "Kind": "fuel_can"}
+  ]
+}`, []int{3, 4, 5, 5, 6, 9, 12}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse([]byte(test.script))
+			if err == nil {
+				t.Fatal("the script is accepted")
+			}
+			if lines := errorLines(err); !slices.Equal(lines, test.lines) {
+				t.Fatalf("the errors are on lines %v, want %v: %v", lines, test.lines, err)
+			}
+		})
+	}
+}
+
+func TestValidateWave(t *testing.T) {
+	tests := []struct {
+		name  string
+		wave  Wave
+		valid bool
+	}{
+		{"wall", Wave{Pattern: PatternWall, Vehicle: VehicleCar, Lanes: []int{1, 3, 5}}, true},
+		{"slalom", Wave{Pattern: PatternSlalom, Vehicle: VehicleTruck, Lanes: []int{2, 4}, Count: 6}, true},
+		{"convoy", Wave{Pattern: PatternConvoy, Vehicle: VehicleLongTruck, Lanes: []int{3}, Count: 3}, true},
+		{"no lanes", Wave{Pattern: PatternWall, Vehicle: VehicleCar}, false},
+		{"duplicate lanes", Wave{Pattern: PatternWall, Vehicle: VehicleCar, Lanes: []int{1, 1, 2}}, false},
+		{"duplicate slalom lanes", Wave{Pattern: PatternSlalom, Vehicle: VehicleCar, Lanes: []int{2, 4, 2}, Count: 3}, false},
+		{"closed road", Wave{Pattern: PatternWall, Vehicle: VehicleCar, Lanes: []int{1, 2, 3, 4, 5}}, false},
+		{"no count", Wave{Pattern: PatternSlalom, Vehicle: VehicleCar, Lanes: []int{1, 2}}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			messages := test.wave.validate()
+			if test.valid && len(messages) != 0 {
+				t.Fatalf("the wave is rejected: %s", strings.Join(messages, ", "))
+			}
+			if !test.valid && len(messages) == 0 {
+				t.Fatal("the wave is accepted")
+			}
+		})
+	}
+}
+
+func TestValidateLanes(t *testing.T) {
+	wall := func(at float64, lanes ...int) *Wave {
+		return &Wave{At: at, Pattern: PatternWall, Vehicle: VehicleCar, Lanes: lanes}
+	}
+	tests := []struct {
+		name   string
+		script Script
+		valid  bool
+	}{
+		{"walls one lane at a time", Script{Waves: []*Wave{wall(100, 1), wall(100, 2), wall(100, 3), wall(100, 4), wall(100, 5)}}, false},
+		{"walls a car length apart", Script{Waves: []*Wave{wall(100, 1, 2, 3), wall(100+carLength, 4, 5)}}, true},
+		{"walls closer than a car length", Script{Waves: []*Wave{wall(100, 1, 2, 3), wall(110, 4, 5)}}, false},
+		{"barrier in the open lane", Script{
+			Waves:   []*Wave{wall(100, 1, 2, 3, 4)},
+			Hazards: []*Item{{At: 105, Lane: 5, Kind: "barrier"}},
+		}, false},
+		{"oil slick in the open lane", Script{
+			Waves:   []*Wave{wall(100, 1, 2, 3, 4)},
+			Hazards: []*Item{{At: 105, Lane: 5, Kind: "oil_slick"}},
+		}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.script.Validate()
+			if test.valid && err != nil {
+				t.Fatalf("the script is rejected: %v", err)
+			}
+			if !test.valid && err == nil {
+				t.Fatal("the script is accepted")
+			}
+		})
+	}
+}
+
+func TestClone(t *testing.T) {
+	script := &Script{
+		Waves:   []*Wave{{At: 100, Pattern: PatternWall, Vehicle: VehicleCar, Lanes: []int{1, 2}}},
+		Hazards: []*Item{{At: 200, Lane: 3, Kind: "cone"}},
+	}
+	clone := script.Clone()
+	clone.Waves[0].Lanes[0] = 5
+	clone.Waves[0].At = 150
+	clone.Hazards[0].Lane = 4
+	clone.Waves = append(clone.Waves, &Wave{At: 300})
+	if script.Waves[0].Lanes[0] != 1 || script.Waves[0].At != 100 || script.Hazards[0].Lane != 3 || len(script.Waves) != 1 {
+		t.Fatalf("changing the clone has changed the script: %+v", script)
+	}
+}
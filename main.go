@@ -1,15 +1,82 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 
+	"github.com/VxVxN/game/internal/datadir"
 	"github.com/VxVxN/game/internal/game"
+	"github.com/VxVxN/game/internal/settings"
+	"github.com/VxVxN/game/pkg/replay"
 )
 
 func main() {
-	game, err := game.NewGame()
+	dataDirPath := flag.String("data-dir", "", "directory the settings, profiles and records are kept in (default: the config directory of the user)")
+	windowed := flag.Bool("windowed", false, "play in a window, 1280x720 if full screen is saved")
+	resolution := flag.String("resolution", "", "play in a window of the size, e.g. 1600x900")
+	fullscreen := flag.Bool("fullscreen", false, "play in full screen")
+	mute := flag.Bool("mute", false, "turn the music and the effects off")
+	var seed *uint64
+	flag.Func("seed", "drive every run on the seed", func(value string) error {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		seed = &parsed
+		return err
+	})
+	mode := flag.String("mode", "", "start a run of the mode right away: endless, time_attack, zen, pursuit or versus")
+	profileName := flag.String("profile", "", "play as the profile with the name or the ID")
+	logLevel := flag.String("log-level", "debug", "the least level written to the log: debug, info, warn or error")
+	replayPath := flag.String("replay", "", "watch the replay in the file")
+	flag.Usage = usage
+	flag.Parse()
+
+	dataDir, err := datadir.New(*dataDirPath)
+	if err != nil {
+		log.Fatalf("Failed to find the data directory: %v", err)
+	}
+	switch flag.Arg(0) {
+	case "":
+	case "verify":
+		os.Exit(verify(flag.Args()[1:]))
+	case "stats":
+		os.Exit(stats(dataDir, *profileName, *mode, flag.Args()[1:]))
+	case "replay":
+		os.Exit(replayInfo(flag.Args()[1:]))
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", flag.Arg(0))
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	options := game.Options{
+		DataDir: dataDir,
+		Seed:    seed,
+		Mode:    *mode,
+		Profile: *profileName,
+	}
+	if options.Session, err = session(*windowed, *fullscreen, *resolution, *mute); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err = options.LogLevel.UnmarshalText([]byte(*logLevel)); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid log level %q\n", *logLevel)
+		os.Exit(2)
+	}
+	if *replayPath != "" {
+		if options.Replay, err = replay.Load(*replayPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+
+	game, err := game.NewGame(options)
 	if err != nil {
 		log.Fatalf("Failed to init game: %v", err)
 	}
@@ -21,3 +88,110 @@ func main() {
 		log.Fatalf("Failed to run game: %v", err)
 	}
 }
+
+func usage() {
+	output := flag.CommandLine.Output()
+	fmt.Fprintln(output, "usage: racer [options]")
+	fmt.Fprintln(output, "       racer [--data-dir dir] [--profile name] [--mode id] stats")
+	fmt.Fprintln(output, "       racer replay info <replay>")
+	fmt.Fprintln(output, "       racer verify <replay>")
+	fmt.Fprintln(output, "\nThe options change the settings until the game is closed, they aren't saved.")
+	flag.PrintDefaults()
+}
+
+// session makes the settings of the launch options, only one of the window sizes can be given.
+func session(windowed, fullscreen bool, resolution string, mute bool) (settings.Session, error) {
+	session := settings.Session{Windowed: windowed, Mute: mute}
+	var sizes int
+	for _, given := range []bool{windowed, fullscreen, resolution != ""} {
+		if given {
+			sizes++
+		}
+	}
+	if sizes > 1 {
+		return session, errors.New("only one of --windowed, --fullscreen and --resolution can be given")
+	}
+	if fullscreen {
+		session.Resolution = settings.ResolutionFullScreen
+	}
+	if resolution != "" {
+		size, err := settings.ParseResolution(resolution)
+		if err != nil {
+			return session, fmt.Errorf("invalid resolution: %v", err)
+		}
+		session.Resolution = size
+	}
+	return session, nil
+}
+
+// stats prints the leaderboards and the history without opening a window.
+func stats(dataDir datadir.Dir, profileName, mode string, args []string) int {
+	if len(args) != 0 {
+		fmt.Fprintln(os.Stderr, "usage: racer [--data-dir dir] [--profile name] [--mode id] stats")
+		return 2
+	}
+	if err := game.PrintStats(os.Stdout, dataDir, profileName, mode); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// replayInfo prints what a replay was driven with without driving it.
+func replayInfo(args []string) int {
+	if len(args) != 2 || args[0] != "info" {
+		fmt.Fprintln(os.Stderr, "usage: racer replay info <replay>")
+		return 2
+	}
+	run, err := replay.Load(args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	vehicle := run.Vehicle
+	if vehicle == "" {
+		vehicle = "the default car"
+	}
+	upgrades := make([]string, 0, len(run.Upgrades))
+	for upgrade := range run.Upgrades {
+		upgrades = append(upgrades, upgrade)
+	}
+	slices.Sort(upgrades)
+	ticks := run.Ticks()
+	fmt.Printf("Mode:     %s\n", run.Mode)
+	fmt.Printf("Version:  %s\n", run.Version)
+	fmt.Printf("Seed:     %d\n", run.Seed)
+	fmt.Printf("Vehicle:  %s\n", vehicle)
+	for _, upgrade := range upgrades {
+		fmt.Printf("          %s level %d\n", upgrade, run.Upgrades[upgrade])
+	}
+	fmt.Printf("Steering: %v\n", run.Speed)
+	fmt.Printf("Screen:   %.0fx%.0f in a window of %.0fx%.0f\n", run.ScreenWidth, run.ScreenHeight, run.Width, run.Height)
+	fmt.Printf("Length:   %d ticks, %s\n", ticks, time.Duration(ticks)*time.Second/ebiten.DefaultTPS)
+	fmt.Printf("Points:   %d\n", run.Points)
+	if err = run.Validate(); err != nil {
+		fmt.Printf("Invalid:  %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// verify drives the run of a replay again and exits with 0 only if it ends with the points the replay claims.
+func verify(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: racer verify <replay>")
+		return 2
+	}
+	run, err := replay.Load(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	points, err := game.Verify(run)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rejected: %v\n", err)
+		return 1
+	}
+	fmt.Printf("verified: %d points in %s\n", points, run.Mode)
+	return 0
+}
@@ -0,0 +1,49 @@
+// Package atomicfile replaces files at once, so a crash while saving can't leave half of one behind.
+package atomicfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Write replaces the file at path with the data, which is written to a temporary file next to it first.
+func Write(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace file: %v", err)
+	}
+	return nil
+}
+
+// WriteWithBackup replaces the file like Write and keeps the previous one at BackupPath.
+// A previous file that isn't valid JSON is left out, it would replace a good backup.
+func WriteWithBackup(path string, data []byte) error {
+	if previous, err := os.ReadFile(path); err == nil && json.Valid(previous) {
+		if err = Write(BackupPath(path), previous); err != nil {
+			return fmt.Errorf("failed to back up file: %v", err)
+		}
+	}
+	return Write(path, data)
+}
+
+// BackupPath returns where WriteWithBackup keeps the previous file.
+func BackupPath(path string) string {
+	return path + ".bak"
+}
@@ -0,0 +1,51 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func read(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestWriteWithBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.json")
+	for _, data := range []string{`{"n":1}`, `{"n":2}`} {
+		if err := WriteWithBackup(path, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if data := read(t, path); data != `{"n":2}` {
+		t.Fatalf("the file has %s", data)
+	}
+	if data := read(t, BackupPath(path)); data != `{"n":1}` {
+		t.Fatalf("the backup has %s", data)
+	}
+
+	// a corrupt file doesn't replace the good backup
+	if err := os.WriteFile(path, []byte(`{"n":`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteWithBackup(path, []byte(`{"n":3}`)); err != nil {
+		t.Fatal(err)
+	}
+	if data := read(t, BackupPath(path)); data != `{"n":1}` {
+		t.Fatalf("the backup has %s after writing over a corrupt file", data)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("the directory has %d files, a temporary file is left behind", len(entries))
+	}
+}
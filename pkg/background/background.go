@@ -2,10 +2,16 @@ package background
 
 import "github.com/hajimehoshi/ebiten/v2"
 
+// PixelsPerMetre is how far the road scrolls for one metre driven.
+const PixelsPerMetre = 10
+
 type Background struct {
 	screenWidth float64
 	image       *ebiten.Image
+	nextImage   *ebiten.Image
+	blend       float64
 	y           float64
+	distance    float64
 }
 
 func New(image *ebiten.Image, screenWidth float64) *Background {
@@ -16,6 +22,7 @@ func New(image *ebiten.Image, screenWidth float64) *Background {
 }
 
 func (background *Background) Update(scrollSpeed float64) {
+	background.distance += scrollSpeed / PixelsPerMetre
 	background.y += scrollSpeed
 	if background.y > float64(background.image.Bounds().Dy()) {
 		background.y = 0
@@ -23,9 +30,44 @@ func (background *Background) Update(scrollSpeed float64) {
 }
 
 func (background *Background) Draw(screen *ebiten.Image) {
+	background.drawImage(screen, background.image, 1)
+	if background.nextImage != nil && background.blend > 0 {
+		background.drawImage(screen, background.nextImage, float32(background.blend))
+	}
+}
+
+func (background *Background) drawImage(screen, image *ebiten.Image, alpha float32) {
 	for i := -1; i < 5; i++ {
 		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(background.screenWidth/2-float64(background.image.Bounds().Dx())/2, float64(background.image.Bounds().Dy()*i)+background.y)
-		screen.DrawImage(background.image, op)
+		op.GeoM.Translate(background.screenWidth/2-float64(image.Bounds().Dx())/2, float64(image.Bounds().Dy()*i)+background.y)
+		op.ColorScale.ScaleAlpha(alpha)
+		screen.DrawImage(image, op)
 	}
 }
+
+// SetImages crossfades from the current road tile to the next one, blend goes from 0 to 1.
+// Both tiles must have the same size.
+func (background *Background) SetImages(current, next *ebiten.Image, blend float64) {
+	background.image = current
+	background.nextImage = next
+	background.blend = blend
+}
+
+// Distance returns the metres driven since the last reset.
+func (background *Background) Distance() float64 {
+	return background.distance
+}
+
+// SetDistance moves the run to the distance without scrolling through it.
+func (background *Background) SetDistance(distance float64) {
+	background.distance = distance
+}
+
+func (background *Background) Width() float64 {
+	return float64(background.image.Bounds().Dx())
+}
+
+func (background *Background) Reset() {
+	background.y = 0
+	background.distance = 0
+}
@@ -0,0 +1,173 @@
+package leaderboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/VxVxN/game/pkg/atomicfile"
+	"github.com/VxVxN/game/pkg/statisticer"
+)
+
+const requestTimeout = 5 * time.Second
+
+var (
+	// ErrRejected is returned for a record the server has refused, sending it again won't help.
+	ErrRejected = errors.New("the leaderboard has rejected the record")
+	// ErrCorruptQueue is returned once for a queue file that can't be read back. It is moved aside with
+	// the .corrupt extension, so the records after it can be sent.
+	ErrCorruptQueue = errors.New("the queue of unsent records is corrupt")
+)
+
+// Client talks to the online leaderboard. Records that can't be sent are kept in a queue file
+// and sent with the next submission.
+type Client struct {
+	baseURL   string
+	queuePath string
+	http      *http.Client
+	mutex     sync.Mutex // guards the queue file
+}
+
+func NewClient(baseURL, queuePath string) *Client {
+	return &Client{
+		baseURL:   baseURL,
+		queuePath: queuePath,
+		http:      &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Submit queues the record and sends everything in the queue. A record that can't be sent
+// because the server is unreachable stays queued and nil is returned.
+func (client *Client) Submit(record statisticer.Record) error {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	queue, err := client.loadQueue()
+	if err != nil && !errors.Is(err, ErrCorruptQueue) {
+		return err
+	}
+	corrupt := err
+	if err = client.saveQueue(append(queue, record)); err != nil {
+		return err
+	}
+	return errors.Join(corrupt, client.flush())
+}
+
+// Flush sends the queued records, e.g. when the game starts after having been offline.
+func (client *Client) Flush() error {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	return client.flush()
+}
+
+// Queued returns how many records are waiting to be sent.
+func (client *Client) Queued() (int, error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	queue, err := client.loadQueue()
+	return len(queue), err
+}
+
+// flush sends the queue in order and stops at the first record that can't be delivered, the mutex must be held.
+func (client *Client) flush() error {
+	queue, err := client.loadQueue()
+	if err != nil && !errors.Is(err, ErrCorruptQueue) {
+		return err
+	}
+	rejected := err
+	for len(queue) > 0 {
+		err = client.send(queue[0])
+		if errors.Is(err, ErrRejected) {
+			rejected = errors.Join(rejected, err)
+		} else if err != nil {
+			break // offline, the rest waits for the next time
+		}
+		queue = queue[1:]
+	}
+	if saveErr := client.saveQueue(queue); saveErr != nil {
+		return saveErr
+	}
+	return rejected
+}
+
+func (client *Client) send(record statisticer.Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %v", err)
+	}
+	response, err := client.http.Post(client.baseURL+"/scores", "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to send record: %v", err)
+	}
+	defer response.Body.Close()
+	switch {
+	case response.StatusCode >= 200 && response.StatusCode < 300:
+		return nil
+	case response.StatusCode >= 400 && response.StatusCode < 500 && response.StatusCode != http.StatusTooManyRequests:
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("%w: %s %s", ErrRejected, response.Status, bytes.TrimSpace(message))
+	}
+	return fmt.Errorf("failed to send record: %s", response.Status)
+}
+
+// Top returns the best records of the mode, the server sorts them by points.
+func (client *Client) Top(mode string, limit int) ([]statisticer.Record, error) {
+	query := url.Values{}
+	query.Set("mode", mode)
+	query.Set("limit", strconv.Itoa(limit))
+	response, err := client.http.Get(client.baseURL + "/scores?" + query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scores: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get scores: %s", response.Status)
+	}
+	var records []statisticer.Record
+	if err = json.NewDecoder(response.Body).Decode(&records); err != nil {
+		return nil, fmt.Errorf("failed to decode scores: %v", err)
+	}
+	return records, nil
+}
+
+func (client *Client) loadQueue() ([]statisticer.Record, error) {
+	data, err := os.ReadFile(client.queuePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queue: %v", err)
+	}
+	var queue []statisticer.Record
+	if err = json.Unmarshal(data, &queue); err != nil {
+		if renameErr := os.Rename(client.queuePath, client.queuePath+".corrupt"); renameErr != nil {
+			return nil, fmt.Errorf("failed to move the corrupt queue aside: %v", renameErr)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrCorruptQueue, err)
+	}
+	return queue, nil
+}
+
+func (client *Client) saveQueue(queue []statisticer.Record) error {
+	if len(queue) == 0 {
+		if err := os.Remove(client.queuePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove queue: %v", err)
+		}
+		return nil
+	}
+	data, err := json.Marshal(queue)
+	if err != nil {
+		return fmt.Errorf("failed to marshal queue: %v", err)
+	}
+	if err = atomicfile.Write(client.queuePath, data); err != nil {
+		return fmt.Errorf("failed to write queue: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package leaderboard
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VxVxN/game/internal/leaderboardserver"
+	"github.com/VxVxN/game/pkg/statisticer"
+)
+
+// stub answers the submissions with the status and keeps the points of the ones it has accepted.
+type stub struct {
+	mutex    sync.Mutex
+	status   int
+	received []int
+}
+
+func (stub *stub) setStatus(status int) {
+	stub.mutex.Lock()
+	defer stub.mutex.Unlock()
+	stub.status = status
+}
+
+func (stub *stub) points() []int {
+	stub.mutex.Lock()
+	defer stub.mutex.Unlock()
+	return slices.Clone(stub.received)
+}
+
+func (stub *stub) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	stub.mutex.Lock()
+	defer stub.mutex.Unlock()
+	var record statisticer.Record
+	if err := json.NewDecoder(request.Body).Decode(&record); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if stub.status >= 200 && stub.status < 300 {
+		stub.received = append(stub.received, record.Points)
+	}
+	writer.WriteHeader(stub.status)
+}
+
+func newStub(t *testing.T, status int) (*stub, *httptest.Server) {
+	stub := &stub{status: status}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	return stub, server
+}
+
+func newTestClient(t *testing.T, url string) *Client {
+	return NewClient(url, filepath.Join(t.TempDir(), "queue.json"))
+}
+
+func queued(t *testing.T, client *Client) int {
+	t.Helper()
+	count, err := client.Queued()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestSubmitAccepted(t *testing.T) {
+	stub, server := newStub(t, http.StatusCreated)
+	client := newTestClient(t, server.URL)
+	if err := client.Submit(statisticer.NewRecord("Player", 100, "Forest")); err != nil {
+		t.Fatal(err)
+	}
+	if count := queued(t, client); count != 0 {
+		t.Fatalf("%d records are queued after they were accepted", count)
+	}
+	if points := stub.points(); !slices.Equal(points, []int{100}) {
+		t.Fatalf("the server has received %v", points)
+	}
+}
+
+func TestSubmitServerError(t *testing.T) {
+	_, server := newStub(t, http.StatusServiceUnavailable)
+	client := newTestClient(t, server.URL)
+	if err := client.Submit(statisticer.NewRecord("Player", 100, "Forest")); err != nil {
+		t.Fatal(err)
+	}
+	if count := queued(t, client); count != 1 {
+		t.Fatalf("%d records are queued, want 1", count)
+	}
+}
+
+func TestSubmitOffline(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close() // the connection is refused
+	client := newTestClient(t, server.URL)
+	if err := client.Submit(statisticer.NewRecord("Player", 100, "Forest")); err != nil {
+		t.Fatal(err)
+	}
+	if count := queued(t, client); count != 1 {
+		t.Fatalf("%d records are queued, want 1", count)
+	}
+}
+
+func TestSubmitRejected(t *testing.T) {
+	_, server := newStub(t, http.StatusUnprocessableEntity)
+	client := newTestClient(t, server.URL)
+	if err := client.Submit(statisticer.NewRecord("Player", 100, "Forest")); !errors.Is(err, ErrRejected) {
+		t.Fatalf("the error is %v, want ErrRejected", err)
+	}
+	if count := queued(t, client); count != 0 {
+		t.Fatalf("%d records are queued after they were rejected", count)
+	}
+}
+
+func TestFlushInOrder(t *testing.T) {
+	stub, server := newStub(t, http.StatusServiceUnavailable)
+	client := newTestClient(t, server.URL)
+	for _, points := range []int{1, 2, 3} {
+		if err := client.Submit(statisticer.NewRecord("Player", points, "Forest")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stub.setStatus(http.StatusCreated)
+	if err := client.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if points := stub.points(); !slices.Equal(points, []int{1, 2, 3}) {
+		t.Fatalf("the server has received %v, want the records in the order they were driven", points)
+	}
+	if count := queued(t, client); count != 0 {
+		t.Fatalf("%d records are queued after the flush", count)
+	}
+}
+
+func TestCorruptQueue(t *testing.T) {
+	stub, server := newStub(t, http.StatusCreated)
+	client := newTestClient(t, server.URL)
+	if err := os.WriteFile(client.queuePath, []byte(`[{"Name":`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Submit(statisticer.NewRecord("Player", 100, "Forest")); !errors.Is(err, ErrCorruptQueue) {
+		t.Fatalf("the error is %v, want ErrCorruptQueue", err)
+	}
+	if points := stub.points(); !slices.Equal(points, []int{100}) {
+		t.Fatalf("the server has received %v", points)
+	}
+	if _, err := os.Stat(client.queuePath + ".corrupt"); err != nil {
+		t.Fatalf("the corrupt queue isn't kept: %v", err)
+	}
+	if err := client.Submit(statisticer.NewRecord("Player", 200, "Forest")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTop(t *testing.T) {
+	entries := []leaderboardserver.Entry{
+		{ID: "a", Record: statisticer.Record{Name: "First", Points: 300, Mode: "endless"}, Submitted: time.Now()},
+		{ID: "b", Record: statisticer.Record{Name: "Second", Points: 200, Mode: "endless"}, Submitted: time.Now()},
+	}
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		query = request.URL.RawQuery
+		_ = json.NewEncoder(writer).Encode(entries)
+	}))
+	defer server.Close()
+
+	records, err := newTestClient(t, server.URL).Top("endless", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "limit=2&mode=endless" {
+		t.Fatalf("the query is %q", query)
+	}
+	if len(records) != 2 || records[0].Name != "First" || records[0].Points != 300 || records[1].Name != "Second" {
+		t.Fatalf("the top is %+v", records)
+	}
+}
@@ -1,6 +1,9 @@
 package player
 
 import (
+	"image"
+	"math"
+
 	"github.com/VxVxN/gamedevlib/rectangle"
 	"github.com/hajimehoshi/ebiten/v2"
 
@@ -14,56 +17,192 @@ type Player struct {
 	speed  float64
 	image  *ebiten.Image
 	shadow *shadow.Shadow
+	tint   ebiten.ColorScale
 	dead   bool
+	// hitbox is where the car can be hit within its image, the rectangle is at the hitbox
+	hitbox   image.Rectangle
+	handling Handling
+	// ahead is how fast the car moves up the road, it builds up while the key is held
+	ahead   float64
+	pressed bool
+	// armour is how many more crashes the car survives in the run
+	armour      int
+	shieldTicks int
+
+	wobbleTicks int
+	slideTicks  int
+	driftX      float64
+}
+
+const (
+	wobbleDuration = 40 // ticks
+	slideDuration  = 60 // ticks, one second
+	shieldDuration = 90 // ticks after a crash the armour has taken, to get away from what was hit
+)
+
+// Handling is how a vehicle drives compared with the steering speed. The default car has 1 for every speed.
+type Handling struct {
+	Steering float64 // left and right
+	// Acceleration is the part of the top speed gained in every tick up or down the road, 1 reaches it at once
+	Acceleration float64
+	TopSpeed     float64 // up the road
+	Brakes       float64 // down the road
+	Armour       int     `json:",omitempty"` // crashes the car survives in a run
 }
 
-func NewPlayer(image *ebiten.Image, shadow *shadow.Shadow, speed float64) *Player {
-	return &Player{
+var DefaultHandling = Handling{Steering: 1, Acceleration: 1, TopSpeed: 1, Brakes: 1}
+
+func NewPlayer(sprite *ebiten.Image, shadow *shadow.Shadow, speed float64) *Player {
+	player := &Player{
 		speed:     speed,
-		Rectangle: rectangle.New(0, 0, float64(image.Bounds().Dx()), float64(image.Bounds().Dy())),
-		image:     image,
-		shadow:    shadow,
+		Rectangle: rectangle.New(0, 0, 0, 0),
+	}
+	player.SetVehicle(sprite, shadow, sprite.Bounds().Sub(sprite.Bounds().Min), DefaultHandling)
+	return player
+}
+
+// SetVehicle changes what the player drives, the hitbox is relative to the image.
+func (player *Player) SetVehicle(sprite *ebiten.Image, vehicleShadow *shadow.Shadow, hitbox image.Rectangle, handling Handling) {
+	if player.shadow != nil {
+		vehicleShadow.SetDirection(player.shadow.DirectionShadow)
 	}
+	player.image = sprite
+	player.shadow = vehicleShadow
+	player.hitbox = hitbox
+	player.handling = handling
+	player.Width = float64(hitbox.Dx())
+	player.Height = float64(hitbox.Dy())
+	player.ahead = 0
+	player.armour = handling.Armour
 }
 
 func (player *Player) Update() {
-	player.points += 0.1
+	if !player.pressed {
+		player.ahead = 0
+	}
+	player.pressed = false
+	if player.shieldTicks > 0 {
+		player.shieldTicks--
+	}
+	if player.wobbleTicks > 0 {
+		player.wobbleTicks--
+		player.X += math.Sin(float64(player.wobbleTicks)*0.8) * 4
+	}
+	if player.slideTicks > 0 {
+		player.slideTicks--
+		player.X += player.driftX
+		if player.slideTicks == 0 {
+			player.driftX = 0
+		}
+	}
+}
+
+// Wobble shakes the car sideways for a moment, e.g. after a pothole.
+func (player *Player) Wobble() {
+	player.wobbleTicks = wobbleDuration
+}
+
+// Slide makes steering build up sideways drift instead of moving the car directly, e.g. on an oil slick.
+func (player *Player) Slide() {
+	player.slideTicks = slideDuration
+}
+
+// Absorb takes a crash with the armour and reports whether the car keeps driving. For a while after it
+// the car can't crash, so it can get away from what it has hit.
+func (player *Player) Absorb() bool {
+	if player.shieldTicks > 0 {
+		return true
+	}
+	if player.armour == 0 {
+		return false
+	}
+	player.armour--
+	player.shieldTicks = shieldDuration
+	player.Wobble()
+	return true
+}
+
+func (player *Player) Sliding() bool {
+	return player.slideTicks > 0
 }
 
 func (player *Player) Draw(screen *ebiten.Image) {
-	player.shadow.Draw(screen, player.X, player.Y)
+	x, y := player.X-float64(player.hitbox.Min.X), player.Y-float64(player.hitbox.Min.Y)
+	player.shadow.Draw(screen, x, y)
 
 	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(player.X, player.Y)
+	op.GeoM.Translate(x, y)
+	op.ColorScale = player.tint
+	if player.shieldTicks/6%2 == 1 {
+		op.ColorScale.ScaleAlpha(0.5) // the car blinks while the armour shields it
+	}
 	screen.DrawImage(player.image, op)
 }
 
+// SetTint recolours the car, e.g. to tell two players apart.
+func (player *Player) SetTint(tint ebiten.ColorScale) {
+	player.tint = tint
+}
+
 func (player *Player) SetPosition(x, y float64) {
 	player.X = x
 	player.Y = y
 }
 
 func (player *Player) Move(key ebiten.Key) {
+	steering := player.speed * player.handling.Steering
+	if player.slideTicks > 0 {
+		switch key {
+		case ebiten.KeyLeft:
+			player.driftX -= steering / 10
+			return
+		case ebiten.KeyRight:
+			player.driftX += steering / 10
+			return
+		}
+	}
 	switch key {
 	case ebiten.KeyLeft:
-		player.X -= player.speed
+		player.X -= steering
 	case ebiten.KeyRight:
-		player.X += player.speed
+		player.X += steering
 	case ebiten.KeyUp:
-		player.Y -= player.speed
+		player.Y -= player.accelerate()
 	case ebiten.KeyDown:
-		player.Y += player.speed
+		player.Y += player.speed * player.handling.Brakes
 	default:
 	}
 }
 
+// accelerate returns how far the car moves up the road in the tick, it stays at the top speed
+// while the key is held and starts again from nothing once it is let go.
+func (player *Player) accelerate() float64 {
+	if !player.pressed {
+		topSpeed := player.speed * player.handling.TopSpeed
+		player.ahead = min(player.ahead+topSpeed*player.handling.Acceleration, topSpeed)
+		player.pressed = true
+	}
+	return player.ahead
+}
+
 func (player *Player) Points() float64 {
 	return player.points
 }
 
+func (player *Player) AddPoints(points float64) {
+	player.points += points
+}
+
 func (player *Player) Reset() {
 	player.points = 0
 	player.dead = false
+	player.wobbleTicks = 0
+	player.slideTicks = 0
+	player.driftX = 0
+	player.ahead = 0
+	player.pressed = false
+	player.armour = player.handling.Armour
+	player.shieldTicks = 0
 }
 
 func (player *Player) SetName(name string) {
@@ -0,0 +1,149 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+)
+
+// Input is the steering of one tick.
+type Input uint8
+
+const (
+	InputLeft Input = 1 << iota
+	InputRight
+	InputUp
+	InputDown
+)
+
+// MinSpeed and MaxSpeed are the range of the steering speed the settings allow.
+const (
+	MinSpeed = 5
+	MaxSpeed = 10
+)
+
+// windowSizes are the windows the settings can open, a window in full screen has the size of the screen.
+var windowSizes = [][2]float64{{1920, 1080}, {1680, 1080}, {1024, 1024}, {1280, 720}}
+
+// The screen is at least as wide as the road and as high as the smallest window, at most an 8K one.
+const (
+	minScreenWidth  = 960
+	minScreenHeight = 720
+	maxScreenWidth  = 7680
+	maxScreenHeight = 4320
+)
+
+// Span is the same input held for a number of ticks in a row.
+type Span struct {
+	Input Input
+	Ticks int
+}
+
+// Replay is everything needed to drive a run again: the world comes from the seed and the sizes,
+// the car from the vehicle, the steering speed and the inputs of every tick.
+type Replay struct {
+	Version string
+	Mode    string
+	Seed    uint64
+	Speed   float64
+	Vehicle string `json:",omitempty"` // the default car if it is empty
+	// Upgrades are the levels of the upgrades the car had
+	Upgrades map[string]int `json:",omitempty"`
+	// ScreenWidth and ScreenHeight are the size the world is laid out for, Width and Height the size of the window.
+	ScreenWidth  float64
+	ScreenHeight float64
+	Width        float64
+	Height       float64
+	Inputs       []Span
+	// Points is the score the run has ended with.
+	Points int
+}
+
+// Record adds the input of the next tick.
+func (replay *Replay) Record(input Input) {
+	if last := len(replay.Inputs) - 1; last >= 0 && replay.Inputs[last].Input == input {
+		replay.Inputs[last].Ticks++
+		return
+	}
+	replay.Inputs = append(replay.Inputs, Span{Input: input, Ticks: 1})
+}
+
+// Ticks returns how long the run has lasted.
+func (replay *Replay) Ticks() int {
+	var ticks int
+	for _, span := range replay.Inputs {
+		ticks += span.Ticks
+	}
+	return ticks
+}
+
+// Expand returns the input of every tick.
+func (replay *Replay) Expand() []Input {
+	inputs := make([]Input, 0, replay.Ticks())
+	for _, span := range replay.Inputs {
+		for range span.Ticks {
+			inputs = append(inputs, span.Input)
+		}
+	}
+	return inputs
+}
+
+// Validate checks that the run was driven by the rules of the game, with a steering speed and in a window
+// the settings allow.
+func (replay *Replay) Validate() error {
+	switch {
+	case replay.Mode == "":
+		return fmt.Errorf("the mode is empty")
+	case replay.Speed < MinSpeed || replay.Speed > MaxSpeed:
+		return fmt.Errorf("the steering speed is %v, not between %d and %d", replay.Speed, MinSpeed, MaxSpeed)
+	case replay.ScreenWidth < minScreenWidth || replay.ScreenHeight < minScreenHeight ||
+		replay.ScreenWidth > maxScreenWidth || replay.ScreenHeight > maxScreenHeight ||
+		replay.ScreenWidth != math.Trunc(replay.ScreenWidth) || replay.ScreenHeight != math.Trunc(replay.ScreenHeight):
+		return fmt.Errorf("the screen is %vx%v", replay.ScreenWidth, replay.ScreenHeight)
+	case !replay.supportedWindow():
+		return fmt.Errorf("the window is %vx%v, the settings can't open it", replay.Width, replay.Height)
+	}
+	for i, span := range replay.Inputs {
+		if span.Ticks <= 0 {
+			return fmt.Errorf("span %d lasts %d ticks", i, span.Ticks)
+		}
+	}
+	return nil
+}
+
+func (replay *Replay) supportedWindow() bool {
+	if replay.Width == replay.ScreenWidth && replay.Height == replay.ScreenHeight {
+		return true
+	}
+	return slices.Contains(windowSizes, [2]float64{replay.Width, replay.Height})
+}
+
+// Difficulty names the steering speed the run was driven with.
+func Difficulty(speed float64) string {
+	return fmt.Sprintf("sensitivity %.1f", speed)
+}
+
+func Load(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay: %v", err)
+	}
+	var replay Replay
+	if err = json.Unmarshal(data, &replay); err != nil {
+		return nil, fmt.Errorf("failed to decode replay: %v", err)
+	}
+	return &replay, nil
+}
+
+func (replay *Replay) Save(path string) error {
+	data, err := json.Marshal(replay)
+	if err != nil {
+		return fmt.Errorf("failed to marshal replay: %v", err)
+	}
+	if err = os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write replay: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package replay
+
+import "testing"
+
+func valid() Replay {
+	return Replay{
+		Mode:         "endless",
+		Speed:        7,
+		ScreenWidth:  1920,
+		ScreenHeight: 1080,
+		Width:        1280,
+		Height:       720,
+		Inputs:       []Span{{Input: InputLeft, Ticks: 10}, {Ticks: 5}},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(run *Replay)
+		valid  bool
+	}{
+		{"valid", func(run *Replay) {}, true},
+		{"slowest steering", func(run *Replay) { run.Speed = MinSpeed }, true},
+		{"fastest steering", func(run *Replay) { run.Speed = MaxSpeed }, true},
+		{"full screen", func(run *Replay) { run.Width, run.Height = 2560, 1440; run.ScreenWidth, run.ScreenHeight = 2560, 1440 }, true},
+		{"no mode", func(run *Replay) { run.Mode = "" }, false},
+		{"no steering", func(run *Replay) { run.Speed = 0 }, false},
+		{"too slow steering", func(run *Replay) { run.Speed = 4.9 }, false},
+		{"too fast steering", func(run *Replay) { run.Speed = 100 }, false},
+		{"no screen", func(run *Replay) { run.ScreenWidth, run.ScreenHeight = 0, 0 }, false},
+		{"screen narrower than the road", func(run *Replay) { run.ScreenWidth = 800 }, false},
+		{"huge screen", func(run *Replay) { run.ScreenWidth, run.ScreenHeight = 100000, 100000 }, false},
+		{"fractional screen", func(run *Replay) { run.ScreenWidth = 1920.5 }, false},
+		{"window the settings can't open", func(run *Replay) { run.Width, run.Height = 1600, 900 }, false},
+		{"no window", func(run *Replay) { run.Width, run.Height = 0, 0 }, false},
+		{"empty span", func(run *Replay) { run.Inputs[1].Ticks = 0 }, false},
+		{"negative span", func(run *Replay) { run.Inputs[0].Ticks = -1 }, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			run := valid()
+			test.change(&run)
+			err := run.Validate()
+			if test.valid && err != nil {
+				t.Fatalf("the replay is rejected: %v", err)
+			}
+			if !test.valid && err == nil {
+				t.Fatal("the replay is accepted")
+			}
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	var run Replay
+	for _, input := range []Input{InputLeft, InputLeft, 0, InputUp | InputRight, InputUp | InputRight, InputUp | InputRight} {
+		run.Record(input)
+	}
+	want := []Span{{InputLeft, 2}, {0, 1}, {InputUp | InputRight, 3}}
+	if len(run.Inputs) != len(want) {
+		t.Fatalf("the spans are %v, want %v", run.Inputs, want)
+	}
+	for i := range want {
+		if run.Inputs[i] != want[i] {
+			t.Fatalf("the spans are %v, want %v", run.Inputs, want)
+		}
+	}
+	if ticks := run.Ticks(); ticks != 6 {
+		t.Fatalf("the run lasts %d ticks, want 6", ticks)
+	}
+	if inputs := run.Expand(); len(inputs) != 6 || inputs[2] != 0 || inputs[5] != InputUp|InputRight {
+		t.Fatalf("the inputs are %v", inputs)
+	}
+}
@@ -0,0 +1,141 @@
+package statisticer
+
+import (
+	"sort"
+	"time"
+)
+
+// Top returns the n records with the most points, the best one first. Records with the same points
+// keep their order, so the older one stays ahead.
+func Top(records []Record, n int) []Record {
+	top := append([]Record(nil), records...)
+	sort.SliceStable(top, func(i, j int) bool {
+		return top[i].Points > top[j].Points
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// ByName returns the records of the player.
+func ByName(records []Record, name string) []Record {
+	var result []Record
+	for _, record := range records {
+		if record.Name == name {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+// PersonalBests returns the best record of every player.
+func PersonalBests(records []Record) map[string]Record {
+	bests := make(map[string]Record)
+	for _, record := range records {
+		if best, ok := bests[record.Name]; !ok || record.Points > best.Points {
+			bests[record.Name] = record
+		}
+	}
+	return bests
+}
+
+type Averages struct {
+	Runs       int
+	Points     float64
+	Distance   float64
+	Duration   time.Duration
+	NearMisses float64
+}
+
+func Average(records []Record) Averages {
+	averages := Averages{Runs: len(records)}
+	if len(records) == 0 {
+		return averages
+	}
+	var duration time.Duration
+	for _, record := range records {
+		averages.Points += float64(record.Points)
+		averages.Distance += record.Distance
+		averages.NearMisses += float64(record.NearMisses)
+		duration += record.Duration
+	}
+	runs := float64(len(records))
+	averages.Points /= runs
+	averages.Distance /= runs
+	averages.NearMisses /= runs
+	averages.Duration = duration / time.Duration(len(records))
+	return averages
+}
+
+// DayStreaks returns how many days in a row there has been a run until today, or until yesterday
+// while there is no run today yet, and the most days in a row there have ever been.
+// Records without a time, like the ones from before the history, don't count.
+func DayStreaks(records []Record, now time.Time) (int, int) {
+	days := make(map[time.Time]bool)
+	for _, record := range records {
+		if !record.Time.IsZero() {
+			days[day(record.Time)] = true
+		}
+	}
+
+	var longest int
+	for date := range days {
+		if days[date.AddDate(0, 0, -1)] {
+			continue // not the first day of a streak
+		}
+		length := 1
+		for days[date.AddDate(0, 0, length)] {
+			length++
+		}
+		longest = max(longest, length)
+	}
+
+	var current int
+	date := day(now)
+	if !days[date] {
+		date = date.AddDate(0, 0, -1)
+	}
+	for days[date] {
+		current++
+		date = date.AddDate(0, 0, -1)
+	}
+	return current, longest
+}
+
+// WinningStreak returns how many of the last runs in a row have beaten the best run before them.
+func WinningStreak(records []Record) int {
+	var streak, best int
+	for i, record := range records {
+		if i > 0 && record.Points > best {
+			streak++
+		} else if i > 0 {
+			streak = 0
+		}
+		best = max(best, record.Points)
+	}
+	return streak
+}
+
+func day(t time.Time) time.Time {
+	year, month, date := t.Local().Date()
+	return time.Date(year, month, date, 0, 0, 0, 0, time.Local)
+}
+
+// Sessions splits the runs into sittings, a pause longer than gap starts the next one.
+// Records without a time, like the ones from before the history, aren't in any session.
+func Sessions(records []Record, gap time.Duration) [][]Record {
+	var sessions [][]Record
+	var last time.Time
+	for _, record := range records {
+		if record.Time.IsZero() {
+			continue
+		}
+		if len(sessions) == 0 || record.Time.Sub(last) > gap {
+			sessions = append(sessions, nil)
+		}
+		sessions[len(sessions)-1] = append(sessions[len(sessions)-1], record)
+		last = record.Time
+	}
+	return sessions
+}
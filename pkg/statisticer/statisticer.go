@@ -2,24 +2,61 @@ package statisticer
 
 import (
 	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/VxVxN/game/pkg/atomicfile"
+	"github.com/VxVxN/game/pkg/replay"
 )
 
+// FormatVersion is the version of the file format Save writes.
+const FormatVersion = 1
+
+// ErrCorrupt is returned by Load for a file that can't be read back, RestoreBackup or Reset repair it.
+var ErrCorrupt = errors.New("the records file is corrupt")
+
 type Record struct {
 	Name   string
 	Points int
+	Biome  string
+	// the run the record was made in, records from before the history only have the fields above
+	Time       time.Time     `json:",omitempty"`
+	Duration   time.Duration `json:",omitempty"`
+	Distance   float64       `json:",omitempty"` // metres
+	MaxSpeed   float64       `json:",omitempty"` // km/h
+	NearMisses int           `json:",omitempty"`
+	Cause      string        `json:",omitempty"` // what the car crashed into, empty when the mode ended the run
+	Mode       string        `json:",omitempty"`
+	Seed       uint64        `json:",omitempty"`
+	Difficulty string        `json:",omitempty"`
+	Version    string        `json:",omitempty"`
+	// Replay lets the online leaderboard drive the run again to check the points, it isn't saved to the file
+	Replay *replay.Replay `json:",omitempty"`
 }
 
-func NewRecord(name string, points int) Record {
+func NewRecord(name string, points int, biome string) Record {
 	return Record{
 		Name:   name,
 		Points: points,
+		Biome:  biome,
 	}
 }
 
+// file is what is written to disk, the version lets later formats read older files.
+type file struct {
+	Version int
+	Records []Record
+}
+
+// Statisticer keeps every finished run as a record in a JSON file, in the order they were driven.
+// Records from the older text file with the same name and the .txt extension are moved over
+// the first time the JSON file is missing.
 type Statisticer struct {
 	pathToSaveFile string
 }
@@ -28,36 +65,148 @@ func NewStatisticer(pathToSaveFile string) *Statisticer {
 	return &Statisticer{pathToSaveFile: pathToSaveFile}
 }
 
+func (s *Statisticer) Path() string {
+	return s.pathToSaveFile
+}
+
+func (s *Statisticer) backupPath() string {
+	return atomicfile.BackupPath(s.pathToSaveFile)
+}
+
+func (s *Statisticer) legacyPath() string {
+	return strings.TrimSuffix(s.pathToSaveFile, filepath.Ext(s.pathToSaveFile)) + ".txt"
+}
+
 func (s *Statisticer) Load() ([]Record, error) {
-	file, err := os.Open(s.pathToSaveFile)
+	records, legacy, err := s.read()
+	if err != nil || !legacy {
+		return records, err
+	}
+	if err = s.Save(records); err != nil {
+		return nil, fmt.Errorf("failed to migrate records: %v", err)
+	}
+	return records, nil
+}
+
+// Read returns the records like Load without writing anything, the ones of the older text file aren't moved over.
+func (s *Statisticer) Read() ([]Record, error) {
+	records, _, err := s.read()
+	return records, err
+}
+
+// read reports whether the records come from the older text file.
+func (s *Statisticer) read() ([]Record, bool, error) {
+	records, err := readFile(s.pathToSaveFile)
+	if !errors.Is(err, os.ErrNotExist) {
+		return records, false, err
+	}
+	records, err = readLegacyFile(s.legacyPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return []Record{}, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return records, true, nil
+}
+
+func readFile(path string) ([]Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var saved file
+	if err = json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	if saved.Version > FormatVersion {
+		return nil, fmt.Errorf("the records file has version %d, this game reads up to %d", saved.Version, FormatVersion)
+	}
+	if saved.Records == nil {
+		saved.Records = []Record{}
+	}
+	return saved.Records, nil
+}
+
+// readLegacyFile reads the name,points[,biome] lines the game wrote before the JSON file. Names could contain
+// commas, so the numbers are looked for from the end of the line, lines that make no sense are skipped.
+func readLegacyFile(path string) ([]Record, error) {
+	legacyFile, err := os.Open(path)
 	if err != nil {
-		return []Record{}, nil
+		return nil, err
 	}
-	defer file.Close()
-	var records []Record
-	scanner := bufio.NewScanner(file)
+	defer legacyFile.Close()
+	records := []Record{}
+	scanner := bufio.NewScanner(legacyFile)
 	for scanner.Scan() {
-		line := scanner.Text()
-		splitLine := strings.Split(line, ",")
-		if len(splitLine) != 2 {
-			return nil, fmt.Errorf("invalid line: %s", line)
+		fields := strings.Split(scanner.Text(), ",")
+		if len(fields) < 2 {
+			continue
+		}
+		if points, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+			records = append(records, NewRecord(strings.Join(fields[:len(fields)-1], ","), points, ""))
+			continue
 		}
-		points, err := strconv.Atoi(splitLine[1])
-		if err != nil {
-			return nil, err
+		if len(fields) < 3 {
+			continue
 		}
-		records = append(records, Record{
-			Name:   splitLine[0],
-			Points: points,
-		})
+		if points, err := strconv.Atoi(fields[len(fields)-2]); err == nil {
+			records = append(records, NewRecord(strings.Join(fields[:len(fields)-2], ","), points, fields[len(fields)-1]))
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read legacy records: %v", err)
 	}
 	return records, nil
 }
 
+// Save writes the records in the order they were driven, the previous file is kept as the backup.
 func (s *Statisticer) Save(records []Record) error {
-	var data string
-	for _, recotd := range records {
-		data += fmt.Sprintf("%s,%d\n", recotd.Name, recotd.Points)
+	saved := file{Version: FormatVersion, Records: make([]Record, len(records))}
+	for i, record := range records {
+		record.Replay = nil
+		saved.Records[i] = record
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal records: %v", err)
+	}
+	return atomicfile.WriteWithBackup(s.pathToSaveFile, data)
+}
+
+// HasBackup reports whether there is a backup RestoreBackup can read.
+func (s *Statisticer) HasBackup() bool {
+	_, err := readFile(s.backupPath())
+	return err == nil
+}
+
+// RestoreBackup puts the backup in place of a corrupt file, which is kept next to it with the .corrupt extension.
+func (s *Statisticer) RestoreBackup() error {
+	if _, err := readFile(s.backupPath()); err != nil {
+		return fmt.Errorf("failed to read backup: %v", err)
+	}
+	data, err := os.ReadFile(s.backupPath())
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %v", err)
+	}
+	if err = s.moveAside(); err != nil {
+		return err
+	}
+	return atomicfile.Write(s.pathToSaveFile, data)
+}
+
+// Reset starts with no records instead of a corrupt file, which is kept next to it with the .corrupt extension.
+func (s *Statisticer) Reset() error {
+	if err := s.moveAside(); err != nil {
+		return err
+	}
+	return s.Save([]Record{})
+}
+
+func (s *Statisticer) moveAside() error {
+	err := os.Rename(s.pathToSaveFile, s.pathToSaveFile+".corrupt")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to move the corrupt file aside: %v", err)
 	}
-	return os.WriteFile(s.pathToSaveFile, []byte(data), 0644)
+	return nil
 }
@@ -0,0 +1,210 @@
+package statisticer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func newTestStatisticer(t *testing.T) *Statisticer {
+	return NewStatisticer(filepath.Join(t.TempDir(), "statistics.json"))
+}
+
+func write(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func load(t *testing.T, statisticer *Statisticer) []Record {
+	t.Helper()
+	records, err := statisticer.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func points(records []Record) []int {
+	points := make([]int, len(records))
+	for i, record := range records {
+		points[i] = record.Points
+	}
+	return points
+}
+
+func TestLoadMissing(t *testing.T) {
+	records := load(t, newTestStatisticer(t))
+	if records == nil || len(records) != 0 {
+		t.Fatalf("the records are %v, want none", records)
+	}
+}
+
+func TestReadLegacyFile(t *testing.T) {
+	statisticer := newTestStatisticer(t)
+	write(t, statisticer.legacyPath(), "Player,100\n"+
+		"a,b,100\n"+
+		"x,5,300,Forest\n"+
+		"Comma, Inc.,250,Desert\n"+
+		"no points\n"+
+		"Player,many\n"+
+		"\n")
+
+	want := []Record{
+		{Name: "Player", Points: 100},
+		{Name: "a,b", Points: 100},
+		{Name: "x,5", Points: 300, Biome: "Forest"},
+		{Name: "Comma, Inc.", Points: 250, Biome: "Desert"},
+	}
+	records := load(t, statisticer)
+	if !slices.Equal(records, want) {
+		t.Fatalf("the records are %+v, want %+v", records, want)
+	}
+
+	// the records are migrated to the JSON file, the text file isn't read again
+	if err := os.Remove(statisticer.legacyPath()); err != nil {
+		t.Fatal(err)
+	}
+	if records = load(t, statisticer); !slices.Equal(records, want) {
+		t.Fatalf("the migrated records are %+v, want %+v", records, want)
+	}
+}
+
+func TestReadDoesNotMigrate(t *testing.T) {
+	statisticer := newTestStatisticer(t)
+	write(t, statisticer.legacyPath(), "Player,100\n")
+	records, err := statisticer.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(points(records), []int{100}) {
+		t.Fatalf("the records are %v", points(records))
+	}
+	if _, err = os.Stat(statisticer.Path()); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("the records file has been written: %v", err)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	statisticer := newTestStatisticer(t)
+	record := NewRecord("Player, the second", 100, "Forest")
+	record.Mode = "endless"
+	record.Seed = 42
+	if err := statisticer.Save([]Record{record, NewRecord("Other", 50, "City")}); err != nil {
+		t.Fatal(err)
+	}
+	records := load(t, statisticer)
+	if len(records) != 2 || records[0] != record || records[1].Name != "Other" {
+		t.Fatalf("the records are %+v", records)
+	}
+}
+
+func TestSaveKeepsBackup(t *testing.T) {
+	statisticer := newTestStatisticer(t)
+	for _, records := range [][]Record{{NewRecord("Player", 1, "")}, {NewRecord("Player", 2, "")}} {
+		if err := statisticer.Save(records); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !statisticer.HasBackup() {
+		t.Fatal("there is no backup")
+	}
+	backup, err := readFile(statisticer.backupPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(points(backup), []int{1}) {
+		t.Fatalf("the backup has %v, want the previous records", points(backup))
+	}
+}
+
+func TestCorruptFile(t *testing.T) {
+	statisticer := newTestStatisticer(t)
+	if err := statisticer.Save([]Record{NewRecord("Player", 1, "")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := statisticer.Save([]Record{NewRecord("Player", 2, "")}); err != nil {
+		t.Fatal(err)
+	}
+	write(t, statisticer.Path(), `{"Version":1,"Records":[{"Name":`)
+
+	if _, err := statisticer.Load(); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("the error is %v, want ErrCorrupt", err)
+	}
+	// saving over the corrupt file keeps the good backup
+	if err := statisticer.Save([]Record{NewRecord("Player", 3, "")}); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := readFile(statisticer.backupPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(points(backup), []int{1}) {
+		t.Fatalf("the backup has %v after saving over a corrupt file", points(backup))
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	statisticer := newTestStatisticer(t)
+	for _, records := range [][]Record{{NewRecord("Player", 1, "")}, {NewRecord("Player", 2, "")}} {
+		if err := statisticer.Save(records); err != nil {
+			t.Fatal(err)
+		}
+	}
+	corrupt := `{"Version":1,"Records":[{"Name":`
+	write(t, statisticer.Path(), corrupt)
+
+	if err := statisticer.RestoreBackup(); err != nil {
+		t.Fatal(err)
+	}
+	if records := load(t, statisticer); !slices.Equal(points(records), []int{1}) {
+		t.Fatalf("the restored records are %v", points(records))
+	}
+	data, err := os.ReadFile(statisticer.Path() + ".corrupt")
+	if err != nil {
+		t.Fatalf("the corrupt file isn't kept: %v", err)
+	}
+	if string(data) != corrupt {
+		t.Fatalf("the corrupt file has %q", data)
+	}
+}
+
+func TestRestoreMissingBackup(t *testing.T) {
+	statisticer := newTestStatisticer(t)
+	write(t, statisticer.Path(), "{")
+	if statisticer.HasBackup() {
+		t.Fatal("there is a backup")
+	}
+	if err := statisticer.RestoreBackup(); err == nil {
+		t.Fatal("a missing backup is restored")
+	}
+	if _, err := os.Stat(statisticer.Path()); err != nil {
+		t.Fatalf("the corrupt file is gone: %v", err)
+	}
+}
+
+func TestReset(t *testing.T) {
+	statisticer := newTestStatisticer(t)
+	write(t, statisticer.Path(), "{")
+	if err := statisticer.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if records := load(t, statisticer); len(records) != 0 {
+		t.Fatalf("the records are %v after the reset", records)
+	}
+	if _, err := os.Stat(statisticer.Path() + ".corrupt"); err != nil {
+		t.Fatalf("the corrupt file isn't kept: %v", err)
+	}
+}
+
+func TestNewerVersion(t *testing.T) {
+	statisticer := newTestStatisticer(t)
+	write(t, statisticer.Path(), `{"Version":2,"Records":[]}`)
+	_, err := statisticer.Load()
+	if err == nil || errors.Is(err, ErrCorrupt) {
+		t.Fatalf("the error is %v, want one about the version", err)
+	}
+}